@@ -0,0 +1,44 @@
+// Package approve is used to approve jobs that are on hold.
+package approve
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/status"
+)
+
+// Known errors.
+var (
+	ErrNotPending = errors.New("job is not an approval job that is on hold")
+)
+
+type client interface {
+	ApproveJob(context.Context, string, string) (string, error)
+}
+
+// Pending returns the approval jobs of the workflow that are on hold.
+func Pending(w *circleci.Workflow) []*circleci.Job {
+	var jobs []*circleci.Job
+	for _, j := range w.Jobs {
+		if isPending(j) {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+func isPending(j *circleci.Job) bool {
+	return j.Type == circleci.JobTypeApproval &&
+		j.Status == status.WorkflowOnHold &&
+		j.ApprovalRequestID != ""
+}
+
+// Job will approve the given job of the workflow.
+func Job(ctx context.Context, c client, w *circleci.Workflow, j *circleci.Job) (string, error) {
+	if !isPending(j) {
+		return "", ErrNotPending
+	}
+	return c.ApproveJob(ctx, w.ID, j.ApprovalRequestID)
+}
@@ -0,0 +1,72 @@
+package approve_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmessi/cci/internal/approve"
+	"github.com/tmessi/cci/internal/circleci"
+)
+
+type testClient struct {
+	workflowID        string
+	approvalRequestID string
+}
+
+func (c *testClient) ApproveJob(_ context.Context, workflowID, approvalRequestID string) (string, error) {
+	c.workflowID = workflowID
+	c.approvalRequestID = approvalRequestID
+	return "Accepted.", nil
+}
+
+func TestApprove(t *testing.T) {
+	w := &circleci.Workflow{
+		ID:   "w1",
+		Name: "deploy",
+		Jobs: []*circleci.Job{
+			{Name: "build", Type: "build", Status: "success"},
+			{Name: "hold-staging", Type: "approval", Status: "success", ApprovalRequestID: "a1"},
+			{Name: "hold-prod", Type: "approval", Status: "on_hold", ApprovalRequestID: "a2"},
+			{Name: "deploy-prod", Type: "build", Status: "blocked"},
+		},
+	}
+
+	pending := approve.Pending(w)
+	if len(pending) != 1 || pending[0].Name != "hold-prod" {
+		t.Fatalf("Pending: got %v, wanted [hold-prod]", pending)
+	}
+
+	tests := []struct {
+		name string
+		job  *circleci.Job
+		err  error
+	}{
+		{"OnHold", w.Jobs[2], nil},
+		{"AlreadyApproved", w.Jobs[1], approve.ErrNotPending},
+		{"NotApproval", w.Jobs[3], approve.ErrNotPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{}
+
+			_, err := approve.Job(context.Background(), client, w, tt.job)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if client.workflowID != w.ID || client.approvalRequestID != tt.job.ApprovalRequestID {
+				t.Errorf("got %s/%s, wanted %s/%s", client.workflowID, client.approvalRequestID, w.ID, tt.job.ApprovalRequestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package artifacts is used to list and download the artifacts of a job.
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"golang.org/x/sync/errgroup"
+)
+
+// Known errors.
+var (
+	ErrNoArtifacts = errors.New("no artifacts found")
+)
+
+// Artifacts is the set of artifacts of a job.
+type Artifacts struct {
+	Items []*circleci.Artifact
+}
+
+func (a *Artifacts) String() string {
+	var b strings.Builder
+	for i, item := range a.Items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%-2d %-50s %s", item.NodeIndex, item.Path, item.URL)
+	}
+	return b.String()
+}
+
+type client interface {
+	Artifacts(context.Context, uint64) ([]*circleci.Artifact, error)
+	DownloadArtifact(context.Context, *circleci.Artifact, io.Writer) error
+}
+
+// List retrieves the artifacts for the given job number.
+func List(ctx context.Context, c client, jobNum uint64) (*Artifacts, error) {
+	items, err := c.Artifacts(ctx, jobNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) <= 0 {
+		return nil, ErrNoArtifacts
+	}
+
+	return &Artifacts{Items: items}, nil
+}
+
+// multipleNodes reports whether the artifacts were uploaded by more than one
+// parallel container.
+func (a *Artifacts) multipleNodes() bool {
+	for _, item := range a.Items {
+		if item.NodeIndex != a.Items[0].NodeIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// Download retrieves the artifacts into dir, keeping the directory layout of
+// each artifact's path. If the artifacts were uploaded by more than one
+// parallel container, which often upload the same paths, each container's
+// artifacts are put in a directory named for its node index. Artifacts are
+// downloaded concurrently, bounded by concurrency. A concurrency less than
+// one is treated as one, like circleci.WithConcurrency.
+func Download(ctx context.Context, c client, a *Artifacts, dir string, concurrency int) error {
+	g, gctx := errgroup.WithContext(ctx)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	g.SetLimit(concurrency)
+
+	byNode := a.multipleNodes()
+	for _, item := range a.Items {
+		item := item // https://golang.org/doc/faq#closures_and_goroutines
+
+		itemDir := dir
+		if byNode {
+			itemDir = filepath.Join(dir, strconv.FormatUint(item.NodeIndex, 10))
+		}
+
+		g.Go(func() error {
+			return download(gctx, c, item, itemDir)
+		})
+	}
+
+	return g.Wait()
+}
+
+func download(ctx context.Context, c client, a *circleci.Artifact, dir string) error {
+	// Clean the path as if it were absolute so it cannot escape dir.
+	path := filepath.Join(dir, filepath.Clean(string(filepath.Separator)+filepath.FromSlash(a.Path)))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := c.DownloadArtifact(ctx, a, f); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", a.Path, err)
+	}
+	return f.Close()
+}
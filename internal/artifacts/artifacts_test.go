@@ -0,0 +1,126 @@
+package artifacts_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmessi/cci/internal/artifacts"
+	"github.com/tmessi/cci/internal/circleci"
+)
+
+type testClient struct {
+	artifacts []*circleci.Artifact
+	err       error
+}
+
+func (c *testClient) Artifacts(_ context.Context, _ uint64) ([]*circleci.Artifact, error) {
+	return c.artifacts, c.err
+}
+
+func (c *testClient) DownloadArtifact(_ context.Context, a *circleci.Artifact, w io.Writer) error {
+	_, err := io.WriteString(w, a.URL)
+	return err
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name      string
+		artifacts []*circleci.Artifact
+		err       error
+		expected  error
+	}{
+		{"Error", nil, errors.New("error"), errors.New("error")},
+		{"NoArtifacts", nil, nil, artifacts.ErrNoArtifacts},
+		{"Artifacts", []*circleci.Artifact{{Path: "coverage.html"}}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := artifacts.List(context.Background(), &testClient{tt.artifacts, tt.err}, 1)
+
+			if tt.expected != nil {
+				if err == nil {
+					t.Fatalf("expected error, but did not get one")
+				}
+				if tt.expected.Error() != err.Error() {
+					t.Errorf("got %q, wanted %q", err.Error(), tt.expected.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if len(a.Items) != len(tt.artifacts) {
+				t.Errorf("got %d artifacts, wanted %d", len(a.Items), len(tt.artifacts))
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	tests := []struct {
+		name      string
+		artifacts []*circleci.Artifact
+		expected  map[string]string
+	}{
+		{
+			"SingleNode",
+			[]*circleci.Artifact{
+				{Path: "coverage.html", URL: "coverage"},
+				{Path: "bin/linux/cci", URL: "binary"},
+				{Path: "../../escape", URL: "escape"},
+			},
+			map[string]string{
+				"coverage.html": "coverage",
+				"bin/linux/cci": "binary",
+				"escape":        "escape",
+			},
+		},
+		{
+			"MultipleNodes",
+			[]*circleci.Artifact{
+				{Path: "test-results/junit.xml", NodeIndex: 0, URL: "node0"},
+				{Path: "test-results/junit.xml", NodeIndex: 1, URL: "node1"},
+				{Path: "coverage.html", NodeIndex: 1, URL: "coverage"},
+			},
+			map[string]string{
+				"0/test-results/junit.xml": "node0",
+				"1/test-results/junit.xml": "node1",
+				"1/coverage.html":          "coverage",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{artifacts: tt.artifacts}
+			dir := t.TempDir()
+
+			a, err := artifacts.List(context.Background(), client, 1)
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if err := artifacts.Download(context.Background(), client, a, dir, 2); err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			for path, want := range tt.expected {
+				got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+				if err != nil {
+					t.Errorf("err: %s", err.Error())
+					continue
+				}
+				if string(got) != want {
+					t.Errorf("%s: got %q, wanted %q", path, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package cancel is used to cancel workflows and jobs.
+package cancel
+
+import (
+	"context"
+
+	"github.com/tmessi/cci/internal/status"
+)
+
+type client interface {
+	CancelWorkflow(context.Context, string) (string, error)
+	CancelJob(context.Context, uint64) (string, error)
+}
+
+// Workflow will cancel the given workflow.
+func Workflow(ctx context.Context, c client, workflowID string) (string, error) {
+	return c.CancelWorkflow(ctx, workflowID)
+}
+
+// Job will cancel the job with the given job number.
+func Job(ctx context.Context, c client, jobNum uint64) (string, error) {
+	return c.CancelJob(ctx, jobNum)
+}
+
+// Running will cancel every running workflow of the newest pipeline in s.
+// It returns the names of the workflows that were canceled.
+func Running(ctx context.Context, c client, s *status.Status) ([]string, error) {
+	if len(s.Pipelines) <= 0 {
+		return nil, nil
+	}
+
+	var canceled []string
+	for _, w := range s.Pipelines[0].Workflows {
+		switch w.Status {
+		case status.WorkflowRunning, status.WorkflowFailing:
+		default:
+			continue
+		}
+
+		if _, err := c.CancelWorkflow(ctx, w.ID); err != nil {
+			return canceled, err
+		}
+		canceled = append(canceled, w.Name)
+	}
+	return canceled, nil
+}
@@ -0,0 +1,86 @@
+package cancel_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tmessi/cci/internal/cancel"
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/status"
+)
+
+type testClient struct {
+	canceled []string
+	err      error
+}
+
+func (c *testClient) CancelWorkflow(_ context.Context, id string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	c.canceled = append(c.canceled, id)
+	return "Accepted.", nil
+}
+
+func (c *testClient) CancelJob(_ context.Context, _ uint64) (string, error) {
+	return "Accepted.", c.err
+}
+
+func TestRunning(t *testing.T) {
+	s := &status.Status{
+		Pipelines: []*circleci.Pipeline{
+			{
+				Workflows: []*circleci.Workflow{
+					{ID: "1", Name: "tests", Status: "running"},
+					{ID: "2", Name: "lint", Status: "success"},
+					{ID: "3", Name: "build", Status: "failing"},
+					{ID: "4", Name: "deploy", Status: "on_hold"},
+				},
+			},
+			{
+				Workflows: []*circleci.Workflow{
+					{ID: "5", Name: "tests", Status: "running"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		canceled []string
+		names    []string
+	}{
+		{"Running", nil, []string{"1", "3"}, []string{"tests", "build"}},
+		{"Error", errors.New("error"), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{err: tt.err}
+
+			names, err := cancel.Running(context.Background(), client, s)
+
+			if tt.err != nil {
+				if err == nil {
+					t.Fatalf("expected error, but did not get one")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(client.canceled, tt.canceled) {
+				t.Errorf("canceled: got %v, wanted %v", client.canceled, tt.canceled)
+			}
+
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("names: got %v, wanted %v", names, tt.names)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package circleci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Artifact is a file that was stored by a job.
+type Artifact struct {
+	Path      string `json:"path" yaml:"path"`
+	NodeIndex uint64 `json:"node_index" yaml:"node_index"`
+	URL       string `json:"url" yaml:"url"`
+}
+
+func (c *Client) baseProjectURL() string {
+	return fmt.Sprintf(
+		"%s/api/v2/project/%s/%s/%s",
+		c.rootURL,
+		c.project.VCSType,
+		c.project.Organization,
+		c.project.Name,
+	)
+}
+
+type artifactListResponse struct {
+	Items         []*Artifact `json:"items"`
+	NextPageToken string      `json:"next_page_token"`
+}
+
+// Artifacts returns the artifacts stored by the job with the given job number.
+//
+// https://circleci.com/docs/api/v2/#operation/getJobArtifacts
+func (c *Client) Artifacts(ctx context.Context, num uint64) ([]*Artifact, error) {
+	url := fmt.Sprintf("%s/%d/artifacts", c.baseProjectURL(), num)
+
+	var artifacts []*Artifact
+	var pageToken string
+	for {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if pageToken != "" {
+			q := req.URL.Query()
+			q.Add("page-token", pageToken)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		alr, err := c.artifactPage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, alr.Items...)
+
+		if alr.NextPageToken == "" {
+			return artifacts, nil
+		}
+		pageToken = alr.NextPageToken
+	}
+}
+
+func (c *Client) artifactPage(ctx context.Context, req *http.Request) (*artifactListResponse, error) {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	alr := artifactListResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&alr); err != nil {
+		return nil, err
+	}
+
+	return &alr, nil
+}
+
+// DownloadArtifact writes the contents of the artifact to w.
+func (c *Client) DownloadArtifact(ctx context.Context, a *Artifact, w io.Writer) error {
+	req, err := http.NewRequest("GET", a.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
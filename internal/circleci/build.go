@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 )
 
@@ -15,8 +14,31 @@ import (
 //
 // https://circleci.com/docs/api/v1/?shell#single-job
 type BuildAction struct {
-	OutputURL string `json:"output_url"`
-	HasOutput bool   `json:"has_output"`
+	OutputURL     string `json:"output_url"`
+	HasOutput     bool   `json:"has_output"`
+	Step          uint64 `json:"step"`
+	Index         uint64 `json:"index"`
+	Status        string `json:"status"`
+	Failed        *bool  `json:"failed"`
+	ExitCode      *int   `json:"exit_code"`
+	RunTimeMillis uint64 `json:"run_time_millis"`
+}
+
+// Running reports whether the action is still running.
+func (b *BuildAction) Running() bool {
+	return b.Status == "running"
+}
+
+// HasFailed reports whether the action failed.
+func (b *BuildAction) HasFailed() bool {
+	if b.Failed != nil && *b.Failed {
+		return true
+	}
+	switch b.Status {
+	case "failed", "timedout", "infrastructure_fail":
+		return true
+	}
+	return b.ExitCode != nil && *b.ExitCode != 0
 }
 
 // BuildStep is used to Marshal the response from CircleCI
@@ -34,7 +56,26 @@ type BuildStep struct {
 //
 // https://circleci.com/docs/api/v1/?shell#single-job
 type BuildResponse struct {
-	Steps []*BuildStep `json:"steps"`
+	Steps     []*BuildStep `json:"steps"`
+	Status    string       `json:"status"`
+	Lifecycle string       `json:"lifecycle"`
+	Nodes     []*BuildNode `json:"node"`
+}
+
+// BuildNode is used to Marshal the response from CircleCI
+// when retreiving a Build. It describes a container the Build
+// runs on, including how to connect to it if SSH is enabled.
+//
+// https://circleci.com/docs/api/v1/?shell#single-job
+type BuildNode struct {
+	PublicIPAddr string `json:"public_ip_addr"`
+	Port         uint64 `json:"port"`
+	SSHEnabled   bool   `json:"ssh_enabled"`
+}
+
+// Finished reports whether the build has finished running.
+func (b *BuildResponse) Finished() bool {
+	return b.Lifecycle == "finished" || b.Lifecycle == "not_run"
 }
 
 // Build retrieves the build for the given build number.
@@ -55,8 +96,7 @@ func (c *Client) Build(ctx context.Context, num uint64) (*BuildResponse, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("response error: %d: %q", resp.StatusCode, body)
+		return nil, newAPIError(resp)
 	}
 
 	br := BuildResponse{}
@@ -83,17 +123,52 @@ func (c *Client) BuildActionOutput(ctx context.Context, b *BuildAction) (string,
 	}
 
 	req, err := http.NewRequest("GET", b.OutputURL, nil)
-	req = req.WithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp)
+	}
 
-	resp, err := c.client.Do(req)
+	aor := []actionOutputResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&aor); err != nil {
+		return "", err
+	}
+	var out string
+	for _, a := range aor {
+		out += a.Message
+	}
+	return out, nil
+}
+
+// LiveActionOutput is used to get the output of a BuildAction that may still
+// be running. Unlike BuildActionOutput, it does not rely on the OutputURL,
+// which is only available once the action has finished, so the output
+// retrieved so far is returned.
+func (c *Client) LiveActionOutput(ctx context.Context, num uint64, b *BuildAction) (string, error) {
+	url := fmt.Sprintf("%s/%d/output/%d/%d", c.baseURL(), num, b.Step, b.Index)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("response error: %d: %q", resp.StatusCode, body)
+		return "", newAPIError(resp)
 	}
 
 	aor := []actionOutputResponse{}
@@ -119,7 +119,7 @@ func TestBuild(t *testing.T) {
 			},
 			"invalid-token",
 			1,
-			errors.New(`response error: 404: "{\n  \"message\" : \"Project not found\"\n}\n"`),
+			circleci.ErrNotFound,
 			nil,
 		},
 	}
@@ -156,9 +156,16 @@ func TestBuild(t *testing.T) {
 				if err == nil {
 					t.Fatalf("did not get error but expected: %s", tt.err.Error())
 				}
-				if err.Error() != tt.err.Error() {
+				if !errors.Is(err, tt.err) {
 					t.Errorf("got %q, wanted %q", err.Error(), tt.err.Error())
 				}
+				var apiErr *circleci.APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("got %T, wanted *circleci.APIError", err)
+				}
+				if apiErr.Message != "Project not found" {
+					t.Errorf("Message: got %q, wanted %q", apiErr.Message, "Project not found")
+				}
 				return
 			}
 
@@ -5,8 +5,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 )
 
+const defaultConcurrency = 8
+
 // Project represents a CircleCI project.
 type Project struct {
 	Name         string
@@ -21,16 +24,60 @@ type Client struct {
 	rootURL string
 	project *Project
 	token   string
+
+	retry       *retryPolicy
+	concurrency int
+}
+
+// Option is used to configure optional settings of a Client.
+type Option func(*Client)
+
+// WithRetries configures how a Client retries requests that fail with a 429,
+// or a 5xx response to a GET, HEAD or DELETE. Other requests, like a POST
+// that triggers a pipeline, may have taken effect, so they are not retried
+// on a 5xx. maxRetries is the number of additional attempts that are made,
+// and maxWait is the longest the Client will wait between attempts.
+// A maxRetries of zero disables retries.
+func WithRetries(maxRetries uint, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.retry.maxRetries = maxRetries
+		c.retry.maxWait = maxWait
+	}
+}
+
+// WithConcurrency limits the number of concurrent requests a Client will make
+// when fetching multiple resources. A value less than one is treated as one.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n < 1 {
+			n = 1
+		}
+		c.concurrency = n
+	}
 }
 
 // New creates a Client.
-func New(client *http.Client, rootURL string, project *Project, token string) *Client {
-	return &Client{
-		client:  client,
-		rootURL: rootURL,
-		project: project,
-		token:   token,
+func New(client *http.Client, rootURL string, project *Project, token string, opts ...Option) *Client {
+	c := &Client{
+		client:      client,
+		rootURL:     rootURL,
+		project:     project,
+		token:       token,
+		retry:       defaultRetryPolicy(),
+		concurrency: defaultConcurrency,
+	}
+
+	for _, o := range opts {
+		o(c)
 	}
+
+	return c
+}
+
+// Concurrency returns the maximum number of concurrent requests the Client
+// makes when fetching multiple resources.
+func (c *Client) Concurrency() int {
+	return c.concurrency
 }
 
 func (c *Client) baseURL() string {
@@ -46,10 +93,45 @@ func (c *Client) baseURL() string {
 // do sends an HTTP request and returns an HTTP response.
 // It is a thin wrapper around http.Client.Do that will
 // set the auth using the Client's token, and set headers.
+// Requests that fail for transient reasons are retried.
 func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
-	req = req.WithContext(ctx)
 	req.SetBasicAuth(c.token, "")
 	req.Header.Set("Accept", "application/json")
 
-	return c.client.Do(req)
+	return c.send(ctx, req)
+}
+
+// send sends an HTTP request, retrying with a jittered exponential backoff
+// when the response is a 429, or a 5xx for an idempotent method. It stops
+// retrying when the retry budget is exhausted or the context is done.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := uint(0); ; attempt++ {
+		r := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		resp, err := c.client.Do(r)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, ok := c.retry.wait(attempt, req.Method, resp)
+		if !ok {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
 }
@@ -0,0 +1,210 @@
+package circleci_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+)
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries uint
+		codes      []int
+		header     http.Header
+		status     int
+		requests   int
+	}{
+		{
+			"ServerErrorThenSuccess",
+			3,
+			[]int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			nil,
+			0,
+			3,
+		},
+		{
+			"RateLimitedWithRetryAfter",
+			3,
+			[]int{http.StatusTooManyRequests, http.StatusOK},
+			http.Header{"Retry-After": []string{"0"}},
+			0,
+			2,
+		},
+		{
+			"RetriesExhausted",
+			2,
+			[]int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			nil,
+			http.StatusTooManyRequests,
+			3,
+		},
+		{
+			"RetryAfterTooLong",
+			3,
+			[]int{http.StatusTooManyRequests, http.StatusOK},
+			http.Header{"Retry-After": []string{"3600"}},
+			http.StatusTooManyRequests,
+			1,
+		},
+		{
+			"NotRetryable",
+			3,
+			[]int{http.StatusNotFound, http.StatusOK},
+			nil,
+			http.StatusNotFound,
+			1,
+		},
+		{
+			"Disabled",
+			0,
+			[]int{http.StatusBadGateway, http.StatusOK},
+			nil,
+			http.StatusBadGateway,
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				code := tt.codes[requests]
+				requests++
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(code)
+				w.Write([]byte(`{"steps": []}`))
+			}))
+			defer ts.Close()
+
+			client := circleci.New(
+				ts.Client(),
+				ts.URL,
+				&circleci.Project{
+					Name:         "cci",
+					Organization: "tmessi",
+					VCSType:      "github",
+				},
+				"valid-token",
+				circleci.WithRetries(tt.maxRetries, 10*time.Millisecond),
+			)
+
+			ctx := context.Background()
+			_, err := client.Build(ctx, 1)
+
+			if requests != tt.requests {
+				t.Errorf("requests: got %d, wanted %d", requests, tt.requests)
+			}
+
+			if tt.status != 0 {
+				var apiErr *circleci.APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("got %v, wanted *circleci.APIError", err)
+				}
+				if apiErr.StatusCode != tt.status {
+					t.Errorf("StatusCode: got %d, wanted %d", apiErr.StatusCode, tt.status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestRetryPost(t *testing.T) {
+	tests := []struct {
+		name     string
+		codes    []int
+		requests int
+		err      bool
+	}{
+		{
+			"ServerErrorNotRetried",
+			[]int{http.StatusBadGateway, http.StatusCreated},
+			1,
+			true,
+		},
+		{
+			"RateLimitedRetried",
+			[]int{http.StatusTooManyRequests, http.StatusCreated},
+			2,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Method: got %s, wanted %s", r.Method, http.MethodPost)
+				}
+				code := tt.codes[requests]
+				requests++
+				w.WriteHeader(code)
+				w.Write([]byte(`{"id": "p1", "number": 1, "state": "created"}`))
+			}))
+			defer ts.Close()
+
+			client := circleci.New(
+				ts.Client(),
+				ts.URL,
+				&circleci.Project{
+					Name:         "cci",
+					Organization: "tmessi",
+					VCSType:      "github",
+				},
+				"valid-token",
+				circleci.WithRetries(3, 10*time.Millisecond),
+			)
+
+			ctx := context.Background()
+			_, err := client.TriggerPipeline(ctx, &circleci.TriggerOptions{Branch: "main"})
+
+			if requests != tt.requests {
+				t.Errorf("requests: got %d, wanted %d", requests, tt.requests)
+			}
+
+			if tt.err != (err != nil) {
+				t.Errorf("err: got %v, wanted error %t", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := circleci.New(
+		ts.Client(),
+		ts.URL,
+		&circleci.Project{
+			Name:         "cci",
+			Organization: "tmessi",
+			VCSType:      "github",
+		},
+		"valid-token",
+		circleci.WithRetries(10, time.Minute),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Build(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, wanted %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,79 @@
+package circleci
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// Sentinel errors for well known API responses. These can be checked
+// against an APIError using errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// APIError is returned when CircleCI responds with an unexpected status code.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Message is the message provided by CircleCI in the response body, if any.
+	Message string
+	// Body is the raw response body.
+	Body []byte
+	// Method is the HTTP method of the request.
+	Method string
+	// URL is the URL of the request.
+	URL string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = fmt.Sprintf("%q", e.Body)
+	}
+	return fmt.Sprintf("response error: %s %s: %d: %s", e.Method, e.URL, e.StatusCode, msg)
+}
+
+// Is reports whether the APIError matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// newAPIError creates an APIError from the response, reading and decoding
+// the response body.
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+
+	er := errorResponse{}
+	if err := json.Unmarshal(body, &er); err == nil {
+		e.Message = er.Message
+	}
+
+	return e
+}
@@ -1,11 +1,12 @@
 package circleci
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -13,28 +14,124 @@ import (
 
 // Job provides a summary of a Job. A workflow contains one or more Jobs.
 type Job struct {
-	ID     string `json:"id"`
-	Number uint64 `json:"job_number"`
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	ID                string     `json:"id" yaml:"id"`
+	Number            uint64     `json:"job_number" yaml:"job_number"`
+	Name              string     `json:"name" yaml:"name"`
+	Status            string     `json:"status" yaml:"status"`
+	Type              string     `json:"type" yaml:"type"`
+	Started           *time.Time `json:"started_at" yaml:"started_at"`
+	Stopped           *time.Time `json:"stopped_at" yaml:"stopped_at"`
+	ApprovalRequestID string     `json:"approval_request_id,omitempty" yaml:"approval_request_id,omitempty"`
 }
 
+// Job types reported by CircleCI.
+const (
+	JobTypeBuild    = "build"
+	JobTypeApproval = "approval"
+)
+
 // Workflow provides a summary of a Workflow. A pipeline is made up of one or
 // more workflows. Each workflow has one or more Jobs.
 type Workflow struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Status string `json:"status"`
-	Jobs   []*Job `json:"-"`
+	ID      string     `json:"id" yaml:"id"`
+	Name    string     `json:"name" yaml:"name"`
+	Status  string     `json:"status" yaml:"status"`
+	Created *time.Time `json:"created_at" yaml:"created_at"`
+	Stopped *time.Time `json:"stopped_at" yaml:"stopped_at"`
+	Jobs    []*Job     `json:"jobs" yaml:"jobs"`
+}
+
+// Commit is the commit a pipeline was run for.
+type Commit struct {
+	Subject string `json:"subject" yaml:"subject"`
+	Body    string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// VCS is the version control information of a pipeline.
+type VCS struct {
+	Revision string  `json:"revision" yaml:"revision"`
+	Branch   string  `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Tag      string  `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Commit   *Commit `json:"commit,omitempty" yaml:"commit,omitempty"`
+}
+
+// Actor is the user that triggered a pipeline.
+type Actor struct {
+	Login     string `json:"login" yaml:"login"`
+	AvatarURL string `json:"avatar_url,omitempty" yaml:"avatar_url,omitempty"`
+}
+
+// Trigger describes how a pipeline was triggered.
+type Trigger struct {
+	// Type is how the pipeline was triggered, e.g. webhook, api or schedule.
+	Type  string `json:"type" yaml:"type"`
+	Actor *Actor `json:"actor,omitempty" yaml:"actor,omitempty"`
 }
 
 // Pipeline provides a summary of a single pipeline execution.
 type Pipeline struct {
-	ID        string      `json:"id"`
-	Number    uint64      `json:"number"`
-	State     string      `json:"state"`
-	Updated   *time.Time  `json:"updated_at"`
-	Workflows []*Workflow `json:"-"`
+	ID        string      `json:"id" yaml:"id"`
+	Number    uint64      `json:"number" yaml:"number"`
+	State     string      `json:"state" yaml:"state"`
+	Created   *time.Time  `json:"created_at" yaml:"created_at"`
+	Updated   *time.Time  `json:"updated_at" yaml:"updated_at"`
+	VCS       *VCS        `json:"vcs,omitempty" yaml:"vcs,omitempty"`
+	Trigger   *Trigger    `json:"trigger,omitempty" yaml:"trigger,omitempty"`
+	Workflows []*Workflow `json:"workflows" yaml:"workflows"`
+}
+
+// PipelineFilter selects which pipelines of a project are returned.
+type PipelineFilter struct {
+	// Branch limits the pipelines to those of a branch.
+	Branch string
+	// Revision limits the pipelines to those of a commit.
+	// It may be an abbreviated SHA.
+	Revision string
+	// Tag limits the pipelines to those of a tag.
+	Tag string
+}
+
+// pageSize is the number of items CircleCI returns in a page.
+const pageSize = 20
+
+// maxSearchPages is the number of pages of pipelines searched for those
+// matching a PipelineFilter that the api cannot filter on.
+const maxSearchPages = 10
+
+// search reports whether pipelines need to be matched against the filter,
+// since the api can only filter by branch.
+func (f *PipelineFilter) search() bool {
+	return f.Revision != "" || f.Tag != ""
+}
+
+func (f *PipelineFilter) match(p *Pipeline) bool {
+	if !f.search() {
+		return true
+	}
+	if p.VCS == nil {
+		return false
+	}
+	if f.Revision != "" && !strings.HasPrefix(p.VCS.Revision, f.Revision) {
+		return false
+	}
+	if f.Tag != "" && p.VCS.Tag != f.Tag {
+		return false
+	}
+	return true
+}
+
+func (f *PipelineFilter) String() string {
+	var parts []string
+	if f.Branch != "" {
+		parts = append(parts, fmt.Sprintf("branch: %s", f.Branch))
+	}
+	if f.Revision != "" {
+		parts = append(parts, fmt.Sprintf("commit: %s", f.Revision))
+	}
+	if f.Tag != "" {
+		parts = append(parts, fmt.Sprintf("tag: %s", f.Tag))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (c *Client) basePipelineListURL() string {
@@ -55,22 +152,26 @@ func (c *Client) basePipelineURL() string {
 }
 
 type pipelineListResponse struct {
-	Items []*Pipeline `json:"items"`
+	Items         []*Pipeline `json:"items"`
+	NextPageToken string      `json:"next_page_token"`
 }
 
 // https://circleci.com/docs/api/v2/#operation/listPipelinesForProject
-func (c *Client) recentPipelines(ctx context.Context, branch string, limit uint64) ([]*Pipeline, error) {
+func (c *Client) pipelinePage(ctx context.Context, branch, pageToken string) (*pipelineListResponse, error) {
 	url := c.basePipelineListURL()
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	q := req.URL.Query()
 	if branch != "" {
-		q := req.URL.Query()
 		q.Add("branch", branch)
-		req.URL.RawQuery = q.Encode()
 	}
+	if pageToken != "" {
+		q.Add("page-token", pageToken)
+	}
+	req.URL.RawQuery = q.Encode()
 
 	resp, err := c.do(ctx, req)
 	if err != nil {
@@ -79,8 +180,7 @@ func (c *Client) recentPipelines(ctx context.Context, branch string, limit uint6
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("response error: %d: %q", resp.StatusCode, body)
+		return nil, newAPIError(resp)
 	}
 
 	plr := pipelineListResponse{}
@@ -89,32 +189,95 @@ func (c *Client) recentPipelines(ctx context.Context, branch string, limit uint6
 		return nil, err
 	}
 
-	if len(plr.Items) <= 0 {
-		return nil, fmt.Errorf("no pipelines for branch: %s", branch)
+	return &plr, nil
+}
+
+// recentPipelines returns up to limit of the most recent pipelines matching
+// the filter. It will follow the next_page_token until enough pipelines
+// have been retrieved or there are no more pages. If the filter cannot be
+// applied by the api, at most maxSearchPages are searched.
+func (c *Client) recentPipelines(ctx context.Context, f *PipelineFilter, limit uint64) ([]*Pipeline, error) {
+	// The limit is user supplied, so only preallocate a single page.
+	size := limit
+	if size > pageSize {
+		size = pageSize
 	}
+	pipelines := make([]*Pipeline, 0, size)
 
-	if uint64(len(plr.Items)) < limit {
-		return plr.Items, nil
+	var pageToken string
+	for page := 1; ; page++ {
+		plr, err := c.pipelinePage(ctx, f.Branch, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plr.Items {
+			if f.match(p) {
+				pipelines = append(pipelines, p)
+			}
+		}
+
+		if uint64(len(pipelines)) >= limit || plr.NextPageToken == "" {
+			break
+		}
+		if f.search() && page >= maxSearchPages {
+			break
+		}
+		pageToken = plr.NextPageToken
 	}
 
-	return plr.Items[:limit], nil
+	if len(pipelines) <= 0 {
+		return nil, fmt.Errorf("no pipelines for %s", f)
+	}
+
+	if uint64(len(pipelines)) < limit {
+		return pipelines, nil
+	}
+
+	return pipelines[:limit], nil
 }
 
 type pipelineWorkflowListReponse struct {
-	Items []*Workflow `json:"items"`
+	Items         []*Workflow `json:"items"`
+	NextPageToken string      `json:"next_page_token"`
 }
 
+// Workflows returns the workflows of the given pipeline. The Jobs of
+// each workflow are not retrieved.
+//
 // https://circleci.com/docs/api/v2/#operation/listWorkflowsByPipelineId
-func (c *Client) workflows(ctx context.Context, p *Pipeline) ([]*Workflow, error) {
+func (c *Client) Workflows(ctx context.Context, p *Pipeline) ([]*Workflow, error) {
 	url := c.basePipelineURL()
 
 	url = fmt.Sprintf("%s/%s/workflow", url, p.ID)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+	var workflows []*Workflow
+	var pageToken string
+	for {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if pageToken != "" {
+			q := req.URL.Query()
+			q.Add("page-token", pageToken)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		pwlr, err := c.workflowPage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, pwlr.Items...)
+
+		if pwlr.NextPageToken == "" {
+			return workflows, nil
+		}
+		pageToken = pwlr.NextPageToken
 	}
+}
 
+func (c *Client) workflowPage(ctx context.Context, req *http.Request) (*pipelineWorkflowListReponse, error) {
 	resp, err := c.do(ctx, req)
 	if err != nil {
 		return nil, err
@@ -122,8 +285,7 @@ func (c *Client) workflows(ctx context.Context, p *Pipeline) ([]*Workflow, error
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("response error: %d: %q", resp.StatusCode, body)
+		return nil, newAPIError(resp)
 	}
 
 	pwlr := pipelineWorkflowListReponse{}
@@ -132,35 +294,54 @@ func (c *Client) workflows(ctx context.Context, p *Pipeline) ([]*Workflow, error
 		return nil, err
 	}
 
-	return pwlr.Items, nil
+	return &pwlr, nil
 }
 
-// Pipelines returns a summary of the most recent Pipeline execution for the given branch.
-func (c *Client) Pipelines(ctx context.Context, branch string, limit uint64) ([]*Pipeline, error) {
-	pipelines, err := c.recentPipelines(ctx, branch, limit)
+// Pipelines returns a summary of the most recent Pipeline executions matching the filter.
+// The workflows and jobs of each pipeline are fetched concurrently, bounded by the
+// Client's concurrency. The first error cancels any outstanding requests.
+func (c *Client) Pipelines(ctx context.Context, f *PipelineFilter, limit uint64) ([]*Pipeline, error) {
+	pipelines, err := c.recentPipelines(ctx, f, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	g := new(errgroup.Group)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.concurrency)
 
 	for _, p := range pipelines {
 		p := p // https://golang.org/doc/faq#closures_and_goroutines
 
 		g.Go(func() error {
-			p.Workflows, err = c.workflows(ctx, p)
+			workflows, err := c.Workflows(gctx, p)
 			if err != nil {
 				return err
 			}
+			p.Workflows = workflows
+			return nil
+		})
+	}
 
-			for _, w := range p.Workflows {
-				w.Jobs, err = c.jobs(ctx, w)
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(c.concurrency)
+
+	for _, p := range pipelines {
+		for _, w := range p.Workflows {
+			w := w // https://golang.org/doc/faq#closures_and_goroutines
+
+			g.Go(func() error {
+				jobs, err := c.jobs(gctx, w)
 				if err != nil {
 					return err
 				}
-			}
-			return nil
-		})
+				w.Jobs = jobs
+				return nil
+			})
+		}
 	}
 
 	if err := g.Wait(); err != nil {
@@ -169,3 +350,79 @@ func (c *Client) Pipelines(ctx context.Context, branch string, limit uint64) ([]
 
 	return pipelines, nil
 }
+
+// Pipeline retrieves the pipeline with the given ID. The Workflows
+// of the pipeline are not retrieved.
+//
+// https://circleci.com/docs/api/v2/#operation/getPipelineById
+func (c *Client) Pipeline(ctx context.Context, pipelineID string) (*Pipeline, error) {
+	url := fmt.Sprintf("%s/%s", c.basePipelineURL(), pipelineID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	p := Pipeline{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// TriggerOptions are used to trigger a new pipeline. At most one
+// of Branch or Tag should be set.
+type TriggerOptions struct {
+	Branch     string                 `json:"branch,omitempty"`
+	Tag        string                 `json:"tag,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// TriggerPipeline triggers a new pipeline for the project.
+// The returned Pipeline only has its ID, Number and State set.
+//
+// https://circleci.com/docs/api/v2/#operation/triggerPipeline
+func (c *Client) TriggerPipeline(ctx context.Context, opts *TriggerOptions) (*Pipeline, error) {
+	url := c.basePipelineListURL()
+
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	p := Pipeline{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,198 @@
+package circleci_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+)
+
+func TestPipelines(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    *circleci.PipelineFilter
+		limit     uint64
+		responses map[string]string
+		expected  []uint64
+		workflows []int
+		jobs      []int
+	}{
+		{
+			"SinglePage",
+			&circleci.PipelineFilter{},
+			2,
+			map[string]string{
+				"/api/v2/project/github/tmessi/cci/pipeline": `{"items": [{"id": "p1", "number": 3}, {"id": "p2", "number": 2}, {"id": "p3", "number": 1}], "next_page_token": "page2"}`,
+				"/api/v2/pipeline/p1/workflow":               `{"items": [{"id": "w1", "name": "test"}], "next_page_token": null}`,
+				"/api/v2/pipeline/p2/workflow":               `{"items": [{"id": "w2", "name": "test"}], "next_page_token": null}`,
+				"/api/v2/workflow/w1/job":                    `{"items": [{"id": "j1", "name": "unit"}], "next_page_token": null}`,
+				"/api/v2/workflow/w2/job":                    `{"items": [{"id": "j2", "name": "unit"}], "next_page_token": null}`,
+			},
+			[]uint64{3, 2},
+			[]int{1, 1},
+			[]int{1, 1},
+		},
+		{
+			"MultiplePages",
+			&circleci.PipelineFilter{},
+			3,
+			map[string]string{
+				"/api/v2/project/github/tmessi/cci/pipeline":                  `{"items": [{"id": "p1", "number": 3}, {"id": "p2", "number": 2}], "next_page_token": "page2"}`,
+				"/api/v2/project/github/tmessi/cci/pipeline?page-token=page2": `{"items": [{"id": "p3", "number": 1}], "next_page_token": "page3"}`,
+				"/api/v2/pipeline/p1/workflow":                                `{"items": [{"id": "w1", "name": "test"}], "next_page_token": "page2"}`,
+				"/api/v2/pipeline/p1/workflow?page-token=page2":               `{"items": [{"id": "w4", "name": "lint"}], "next_page_token": null}`,
+				"/api/v2/pipeline/p2/workflow":                                `{"items": [{"id": "w2", "name": "test"}], "next_page_token": null}`,
+				"/api/v2/pipeline/p3/workflow":                                `{"items": [{"id": "w3", "name": "test"}], "next_page_token": null}`,
+				"/api/v2/workflow/w1/job":                                     `{"items": [{"id": "j1", "name": "unit"}], "next_page_token": "page2"}`,
+				"/api/v2/workflow/w1/job?page-token=page2":                    `{"items": [{"id": "j5", "name": "e2e"}], "next_page_token": null}`,
+				"/api/v2/workflow/w2/job":                                     `{"items": [{"id": "j2", "name": "unit"}], "next_page_token": null}`,
+				"/api/v2/workflow/w3/job":                                     `{"items": [{"id": "j3", "name": "unit"}], "next_page_token": null}`,
+				"/api/v2/workflow/w4/job":                                     `{"items": [{"id": "j4", "name": "gofmt"}], "next_page_token": null}`,
+			},
+			[]uint64{3, 2, 1},
+			[]int{2, 1, 1},
+			[]int{2, 1, 1},
+		},
+		{
+			"Revision",
+			&circleci.PipelineFilter{Revision: "abc123"},
+			2,
+			map[string]string{
+				"/api/v2/project/github/tmessi/cci/pipeline":                  `{"items": [{"id": "p1", "number": 4, "vcs": {"revision": "def456"}}, {"id": "p2", "number": 3, "vcs": {"revision": "abc123f"}}], "next_page_token": "page2"}`,
+				"/api/v2/project/github/tmessi/cci/pipeline?page-token=page2": `{"items": [{"id": "p3", "number": 2, "vcs": {"revision": "def456"}}, {"id": "p4", "number": 1, "vcs": {"revision": "abc123f"}}], "next_page_token": null}`,
+				"/api/v2/pipeline/p2/workflow":                                `{"items": [{"id": "w2", "name": "test"}], "next_page_token": null}`,
+				"/api/v2/pipeline/p4/workflow":                                `{"items": [{"id": "w4", "name": "test"}], "next_page_token": null}`,
+				"/api/v2/workflow/w2/job":                                     `{"items": [{"id": "j2", "name": "unit"}], "next_page_token": null}`,
+				"/api/v2/workflow/w4/job":                                     `{"items": [{"id": "j4", "name": "unit"}], "next_page_token": null}`,
+			},
+			[]uint64{3, 1},
+			[]int{1, 1},
+			[]int{1, 1},
+		},
+		{
+			"Tag",
+			&circleci.PipelineFilter{Tag: "v1.0.0"},
+			1,
+			map[string]string{
+				"/api/v2/project/github/tmessi/cci/pipeline": `{"items": [{"id": "p1", "number": 2, "vcs": {"revision": "def456", "branch": "main"}}, {"id": "p2", "number": 1, "vcs": {"revision": "abc123", "tag": "v1.0.0"}}], "next_page_token": null}`,
+				"/api/v2/pipeline/p2/workflow":               `{"items": [{"id": "w2", "name": "release"}], "next_page_token": null}`,
+				"/api/v2/workflow/w2/job":                    `{"items": [{"id": "j2", "name": "publish"}], "next_page_token": null}`,
+			},
+			[]uint64{1},
+			[]int{1},
+			[]int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				res, ok := tt.responses[r.URL.RequestURI()]
+				if !ok {
+					t.Errorf("unexpected request: %s", r.URL.RequestURI())
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(res))
+			}))
+			defer ts.Close()
+
+			client := circleci.New(
+				ts.Client(),
+				ts.URL,
+				&circleci.Project{
+					Name:         "cci",
+					Organization: "tmessi",
+					VCSType:      "github",
+				},
+				"valid-token",
+			)
+
+			ctx := context.Background()
+			pipelines, err := client.Pipelines(ctx, tt.filter, tt.limit)
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if len(pipelines) != len(tt.expected) {
+				t.Fatalf("Pipelines: got %d, wanted %d", len(pipelines), len(tt.expected))
+			}
+
+			for i, p := range pipelines {
+				if p.Number != tt.expected[i] {
+					t.Errorf("Number: got %d, want %d", p.Number, tt.expected[i])
+				}
+
+				if len(p.Workflows) != tt.workflows[i] {
+					t.Fatalf("Workflows: got %d, want %d", len(p.Workflows), tt.workflows[i])
+				}
+
+				if len(p.Workflows[0].Jobs) != tt.jobs[i] {
+					t.Errorf("Jobs: got %d, want %d", len(p.Workflows[0].Jobs), tt.jobs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPipelinesConcurrency(t *testing.T) {
+	const (
+		concurrency = 2
+		pipelines   = 6
+	)
+
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/cci/pipeline"):
+			items := make([]string, 0, pipelines)
+			for i := 1; i <= pipelines; i++ {
+				items = append(items, fmt.Sprintf(`{"id": "p%d", "number": %d}`, i, i))
+			}
+			fmt.Fprintf(w, `{"items": [%s], "next_page_token": null}`, strings.Join(items, ", "))
+		case strings.HasSuffix(r.URL.Path, "/workflow"):
+			fmt.Fprintf(w, `{"items": [{"id": "w-%s", "name": "test"}], "next_page_token": null}`, path.Base(path.Dir(r.URL.Path)))
+		default:
+			w.Write([]byte(`{"items": [{"id": "j1", "name": "unit"}], "next_page_token": null}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := circleci.New(
+		ts.Client(),
+		ts.URL,
+		&circleci.Project{
+			Name:         "cci",
+			Organization: "tmessi",
+			VCSType:      "github",
+		},
+		"valid-token",
+		circleci.WithConcurrency(concurrency),
+	)
+
+	if _, err := client.Pipelines(context.Background(), &circleci.PipelineFilter{Branch: "main"}, pipelines); err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if got := atomic.LoadInt32(&maxInFlight); got != concurrency {
+		t.Errorf("max requests in flight: got %d, wanted %d", got, concurrency)
+	}
+}
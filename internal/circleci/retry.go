@@ -0,0 +1,106 @@
+package circleci
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultMaxWait    = 30 * time.Second
+	baseWait          = 500 * time.Millisecond
+)
+
+// retryPolicy determines if and when a request should be retried.
+type retryPolicy struct {
+	maxRetries uint
+	maxWait    time.Duration
+}
+
+func defaultRetryPolicy() *retryPolicy {
+	return &retryPolicy{
+		maxRetries: defaultMaxRetries,
+		maxWait:    defaultMaxWait,
+	}
+}
+
+// retryable reports whether a request with the method that received the
+// status code can be sent again. A 429 means the request was rejected before
+// it ran, so it can always be retried. A 5xx may arrive after the server
+// applied the request, so only idempotent methods are retried, otherwise
+// e.g. a second pipeline could be triggered.
+func retryable(method string, code int) bool {
+	if code == http.StatusTooManyRequests {
+		return true
+	}
+	if code < http.StatusInternalServerError {
+		return false
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// wait returns how long to wait before retrying the request with the method
+// that produced resp. It returns false if the request should not be retried.
+func (p *retryPolicy) wait(attempt uint, method string, resp *http.Response) (time.Duration, bool) {
+	if !retryable(method, resp.StatusCode) || attempt >= p.maxRetries {
+		return 0, false
+	}
+
+	if d, ok := headerWait(resp.Header); ok {
+		if d > p.maxWait {
+			return 0, false
+		}
+		return d, true
+	}
+
+	backoff := baseWait << attempt
+	if backoff <= 0 || backoff > p.maxWait {
+		backoff = p.maxWait
+	}
+	// Full jitter, see:
+	// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+	return time.Duration(rand.Int63n(int64(backoff) + 1)), true
+}
+
+// headerWait determines how long to wait from the Retry-After or
+// X-RateLimit-* response headers.
+//
+// https://circleci.com/docs/api-developers-guide/#rate-limits
+func headerWait(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if s, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Duration(s) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(time.Until(t)), true
+		}
+	}
+
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	// The reset may be given as a unix timestamp or as a number of seconds.
+	if reset > 1e9 {
+		return nonNegative(time.Until(time.Unix(reset, 0))), true
+	}
+	return time.Duration(reset) * time.Second, true
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,84 @@
+package circleci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TestResult values reported by CircleCI.
+const (
+	TestSuccess = "success"
+	TestFailure = "failure"
+	TestSkipped = "skipped"
+)
+
+// TestMetadata is the result of a single test run by a job.
+type TestMetadata struct {
+	Message   string  `json:"message"`
+	Source    string  `json:"source"`
+	RunTime   float64 `json:"run_time"`
+	File      string  `json:"file"`
+	Result    string  `json:"result"`
+	Name      string  `json:"name"`
+	Classname string  `json:"classname"`
+}
+
+type testMetadataListResponse struct {
+	Items         []*TestMetadata `json:"items"`
+	NextPageToken string          `json:"next_page_token"`
+}
+
+// TestMetadata returns the test results of the job with the given job number.
+//
+// https://circleci.com/docs/api/v2/#operation/getTests
+func (c *Client) TestMetadata(ctx context.Context, num uint64) ([]*TestMetadata, error) {
+	url := fmt.Sprintf("%s/%d/tests", c.baseProjectURL(), num)
+
+	var tests []*TestMetadata
+	var pageToken string
+	for {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if pageToken != "" {
+			q := req.URL.Query()
+			q.Add("page-token", pageToken)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		tlr, err := c.testMetadataPage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, tlr.Items...)
+
+		if tlr.NextPageToken == "" {
+			return tests, nil
+		}
+		pageToken = tlr.NextPageToken
+	}
+}
+
+func (c *Client) testMetadataPage(ctx context.Context, req *http.Request) (*testMetadataListResponse, error) {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	tlr := testMetadataListResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&tlr); err != nil {
+		return nil, err
+	}
+
+	return &tlr, nil
+}
@@ -0,0 +1,46 @@
+package circleci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// User is the user that owns a token.
+type User struct {
+	ID    string `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// Me gets the user that owns the client's token. It can be used to
+// validate a token.
+//
+// https://circleci.com/docs/api/v2/#operation/getCurrentUser
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	url := fmt.Sprintf("%s/api/v2/me", c.rootURL)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	u := User{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&u); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
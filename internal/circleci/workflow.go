@@ -1,10 +1,10 @@
 package circleci
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 )
 
@@ -16,7 +16,8 @@ func (c *Client) baseWorkflowURL() string {
 }
 
 type workflowJobListReponse struct {
-	Items []*Job `json:"items"`
+	Items         []*Job `json:"items"`
+	NextPageToken string `json:"next_page_token"`
 }
 
 // https://circleci.com/docs/api/v2/#operation/listWorkflowJobs
@@ -25,11 +26,34 @@ func (c *Client) jobs(ctx context.Context, w *Workflow) ([]*Job, error) {
 
 	url = fmt.Sprintf("%s/%s/job", url, w.ID)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+	var jobs []*Job
+	var pageToken string
+	for {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if pageToken != "" {
+			q := req.URL.Query()
+			q.Add("page-token", pageToken)
+			req.URL.RawQuery = q.Encode()
+		}
+
+		wjl, err := c.jobPage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, wjl.Items...)
+
+		if wjl.NextPageToken == "" {
+			return jobs, nil
+		}
+		pageToken = wjl.NextPageToken
 	}
+}
 
+func (c *Client) jobPage(ctx context.Context, req *http.Request) (*workflowJobListReponse, error) {
 	resp, err := c.do(ctx, req)
 	if err != nil {
 		return nil, err
@@ -37,8 +61,7 @@ func (c *Client) jobs(ctx context.Context, w *Workflow) ([]*Job, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("response error: %d: %q", resp.StatusCode, body)
+		return nil, newAPIError(resp)
 	}
 
 	wjl := workflowJobListReponse{}
@@ -47,24 +70,87 @@ func (c *Client) jobs(ctx context.Context, w *Workflow) ([]*Job, error) {
 		return nil, err
 	}
 
-	return wjl.Items, nil
+	return &wjl, nil
 }
 
-type retryResponse struct {
+type messageResponse struct {
 	Message string `json:"message"`
 }
 
-// RetryWorkflow will re-run the given workflow.  The endpoint documentation
-// appears to allow specifying the job ids to presumably only run a single job
-// in a workflow. However, this does not seem to be the behavior, instead it
-// runs the full workflow even if a single job is specified.
+// RetryOptions control which jobs of a workflow are re-run.
+// At most one of FromFailed or Jobs should be set. If neither
+// is set, the full workflow is re-run.
+type RetryOptions struct {
+	// FromFailed re-runs the workflow from the failed jobs.
+	FromFailed bool `json:"from_failed,omitempty"`
+	// Jobs are the IDs of the jobs to re-run.
+	Jobs []string `json:"jobs,omitempty"`
+	// EnableSSH re-runs the jobs with SSH enabled. Jobs must be set.
+	EnableSSH bool `json:"enable_ssh,omitempty"`
+}
+
+// RetryResponse is the response from re-running a workflow.
+type RetryResponse struct {
+	Message    string `json:"message"`
+	WorkflowID string `json:"workflow_id"`
+}
+
+func (r *RetryResponse) String() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return fmt.Sprintf("Accepted. New workflow: %s", r.WorkflowID)
+}
+
+// RetryWorkflow will re-run the given workflow. The opts can be used to only
+// re-run the failed jobs, or a selection of jobs, of the workflow.
 //
 // https://circleci.com/docs/api/v2/#operation/rerunWorkflow
-func (c *Client) RetryWorkflow(ctx context.Context, workflowID string) (string, error) {
+func (c *Client) RetryWorkflow(ctx context.Context, workflowID string, opts *RetryOptions) (*RetryResponse, error) {
 	url := c.baseWorkflowURL()
 
 	url = fmt.Sprintf("%s/%s/rerun", url, workflowID)
 
+	if opts == nil {
+		opts = &RetryOptions{}
+	}
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, newAPIError(resp)
+	}
+
+	rr := RetryResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&rr); err != nil {
+		return nil, err
+	}
+	return &rr, nil
+}
+
+// CancelWorkflow will cancel the given workflow.
+//
+// https://circleci.com/docs/api/v2/#operation/cancelWorkflow
+func (c *Client) CancelWorkflow(ctx context.Context, workflowID string) (string, error) {
+	url := c.baseWorkflowURL()
+
+	url = fmt.Sprintf("%s/%s/cancel", url, workflowID)
+
 	req, err := http.NewRequest("POST", url, nil)
 	if err != nil {
 		return "", err
@@ -77,14 +163,109 @@ func (c *Client) RetryWorkflow(ctx context.Context, workflowID string) (string,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusAccepted {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("response error: %d: %q", resp.StatusCode, body)
+		return "", newAPIError(resp)
 	}
 
-	rr := retryResponse{}
+	mr := messageResponse{}
 	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&rr); err != nil {
+	if err := decoder.Decode(&mr); err != nil {
+		return "", err
+	}
+	return mr.Message, nil
+}
+
+// CancelJob will cancel the job with the given job number.
+//
+// https://circleci.com/docs/api/v2/#operation/cancelJob
+func (c *Client) CancelJob(ctx context.Context, num uint64) (string, error) {
+	url := fmt.Sprintf("%s/job/%d/cancel", c.baseProjectURL(), num)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
 		return "", err
 	}
-	return rr.Message, nil
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return "", newAPIError(resp)
+	}
+
+	mr := messageResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&mr); err != nil {
+		return "", err
+	}
+	return mr.Message, nil
+}
+
+// ApproveJob will approve the pending approval job with the given
+// approval request id in the given workflow.
+//
+// https://circleci.com/docs/api/v2/#operation/approvePendingApprovalJobById
+func (c *Client) ApproveJob(ctx context.Context, workflowID, approvalRequestID string) (string, error) {
+	url := c.baseWorkflowURL()
+
+	url = fmt.Sprintf("%s/%s/approve/%s", url, workflowID, approvalRequestID)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", newAPIError(resp)
+	}
+
+	mr := messageResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&mr); err != nil {
+		return "", err
+	}
+	return mr.Message, nil
+}
+
+// Workflow retrieves the workflow with the given ID, along with its jobs.
+//
+// https://circleci.com/docs/api/v2/#operation/getWorkflowById
+func (c *Client) Workflow(ctx context.Context, workflowID string) (*Workflow, error) {
+	url := fmt.Sprintf("%s/%s", c.baseWorkflowURL(), workflowID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	w := Workflow{}
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&w); err != nil {
+		return nil, err
+	}
+
+	w.Jobs, err = c.jobs(ctx, &w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
 }
@@ -4,10 +4,16 @@ package command
 import (
 	"github.com/urfave/cli/v2"
 
+	"github.com/tmessi/cci/internal/command/internal/approve"
+	"github.com/tmessi/cci/internal/command/internal/artifacts"
+	"github.com/tmessi/cci/internal/command/internal/auth"
+	"github.com/tmessi/cci/internal/command/internal/cancel"
 	"github.com/tmessi/cci/internal/command/internal/global"
 	"github.com/tmessi/cci/internal/command/internal/output"
 	"github.com/tmessi/cci/internal/command/internal/retry"
 	"github.com/tmessi/cci/internal/command/internal/status"
+	"github.com/tmessi/cci/internal/command/internal/tests"
+	"github.com/tmessi/cci/internal/command/internal/trigger"
 )
 
 // App returns the cli.App with its subcommands and flags.
@@ -24,6 +30,12 @@ func App() *cli.App {
 		status.Command,
 		output.Command,
 		retry.Command,
+		artifacts.Command,
+		tests.Command,
+		cancel.Command,
+		approve.Command,
+		trigger.Command,
+		auth.Command,
 	}
 
 	return app
@@ -0,0 +1,104 @@
+// Package approve provides the approve subcommand.
+package approve
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tmessi/cci/internal/approve"
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/command/internal/complete"
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/tmessi/cci/internal/status"
+	"github.com/urfave/cli/v2"
+)
+
+// Command is the approve subcommand.
+var Command = &cli.Command{
+	Name:         "approve",
+	ArgsUsage:    "<workflow name> [job name]",
+	Aliases:      []string{"ap"},
+	Usage:        "Approve a job that is on hold",
+	BashComplete: complete.WorkflowJob,
+	Action:       action,
+}
+
+func action(c *cli.Context) error {
+	ctx, cancel := signal.InitContext()
+	defer cancel()
+
+	client, err := global.Client(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	if c.NArg() < 1 || c.NArg() > 2 {
+		return cli.NewExitError("must specify `<workflow name> [job name]`", -1)
+	}
+
+	s, err := status.Check(ctx, client, global.Filter(c), 1)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	workflow := s.Workflow(c.Args().Get(0))
+	if workflow == nil {
+		return cli.NewExitError("workflow not found", exit.CodeNotFound)
+	}
+
+	var job *circleci.Job
+
+	switch c.NArg() {
+	case 2:
+		job = s.Job(workflow.Name, c.Args().Get(1))
+		if job == nil {
+			return cli.NewExitError("job not found", exit.CodeNotFound)
+		}
+	default:
+		pending := approve.Pending(workflow)
+		switch len(pending) {
+		case 0:
+			return cli.NewExitError("no jobs on hold", exit.CodeNotFound)
+		case 1:
+			job = pending[0]
+		default:
+			job, err = choose(os.Stdin, os.Stderr, pending)
+			if err != nil {
+				return cli.NewExitError(err.Error(), -1)
+			}
+		}
+	}
+
+	res, err := approve.Job(ctx, client, workflow, job)
+	if err != nil {
+		return exit.Error(err)
+	}
+	fmt.Println(res)
+	return nil
+}
+
+// choose asks which of the jobs to approve.
+func choose(r io.Reader, w io.Writer, jobs []*circleci.Job) (*circleci.Job, error) {
+	fmt.Fprintln(w, "Multiple jobs are on hold:")
+	for i, j := range jobs {
+		fmt.Fprintf(w, "  %d) %s\n", i+1, j.Name)
+	}
+	fmt.Fprintf(w, "Which job should be approved? [1-%d]: ", len(jobs))
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("no job chosen: %w", err)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(jobs) {
+		return nil, fmt.Errorf("invalid choice: %q", strings.TrimSpace(line))
+	}
+	return jobs[n-1], nil
+}
@@ -0,0 +1,60 @@
+// Package artifacts provides the artifacts subcommand.
+package artifacts
+
+import (
+	"fmt"
+
+	"github.com/tmessi/cci/internal/artifacts"
+	"github.com/tmessi/cci/internal/command/internal/complete"
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/resolve"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/urfave/cli/v2"
+)
+
+// Command is the artifacts subcommand.
+var Command = &cli.Command{
+	Name:         "artifacts",
+	ArgsUsage:    resolve.JobUsage,
+	Aliases:      []string{"art", "a"},
+	Usage:        "List or download the artifacts of a job",
+	BashComplete: complete.Job,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "download",
+			Aliases: []string{"d"},
+			Usage:   "Download the artifacts into `DIR`",
+		},
+	},
+	Action: action,
+}
+
+func action(c *cli.Context) error {
+	ctx, cancel := signal.InitContext()
+	defer cancel()
+
+	client, err := global.Client(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	n, err := resolve.Job(ctx, c, client)
+	if err != nil {
+		return err
+	}
+
+	a, err := artifacts.List(ctx, client, n)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	if dir := c.String("download"); dir != "" {
+		if err := artifacts.Download(ctx, client, a, dir, client.Concurrency()); err != nil {
+			return exit.Error(err)
+		}
+	}
+
+	fmt.Println(a)
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package auth provides the auth subcommand.
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/prompt"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/urfave/cli/v2"
+)
+
+// Command is the auth subcommand.
+var Command = &cli.Command{
+	Name:  "auth",
+	Usage: "Manage the token stored for the CircleCI api",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "login",
+			Usage:  "Validate a token and store it in the credential store",
+			Action: login,
+		},
+		{
+			Name:   "status",
+			Usage:  "Show where the token comes from and who it belongs to",
+			Action: status,
+		},
+		{
+			Name:   "logout",
+			Usage:  "Remove the token from the credential store",
+			Action: logout,
+		},
+	},
+}
+
+func login(c *cli.Context) error {
+	ctx, done := signal.InitContext()
+	defer done()
+
+	// Only a token given explicitly is stored, not one from a profile.
+	token := ""
+	if c.IsSet("token") {
+		token = c.String("token")
+	}
+
+	if token == "" {
+		var err error
+		token, err = prompt.Secret("CircleCI personal api token: ")
+		if err != nil {
+			return cli.NewExitError(err.Error(), -1)
+		}
+	}
+
+	client, err := global.UserClient(c, token)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	user, err := client.Me(ctx)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	store, err := global.Credentials(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	if err := store.Set(c.String("url"), token); err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	fmt.Printf("Logged in to %s as %s, token stored in %s\n", c.String("url"), user.Login, store)
+	return nil
+}
+
+func status(c *cli.Context) error {
+	ctx, done := signal.InitContext()
+	defer done()
+
+	token, source, err := global.Token(c)
+	if errors.Is(err, global.ErrNoToken) {
+		return cli.NewExitError(fmt.Sprintf("not logged in to %s", c.String("url")), exit.CodeUnauthorized)
+	}
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	client, err := global.UserClient(c, token)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	user, err := client.Me(ctx)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	fmt.Printf("Logged in to %s as %s, token from %s\n", c.String("url"), user.Login, source)
+	return nil
+}
+
+func logout(c *cli.Context) error {
+	store, err := global.Credentials(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	if err := store.Delete(c.String("url")); err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	fmt.Printf("Removed token for %s from %s\n", c.String("url"), store)
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package cancel provides the cancel subcommand.
+package cancel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmessi/cci/internal/cancel"
+	"github.com/tmessi/cci/internal/command/internal/complete"
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/tmessi/cci/internal/status"
+	"github.com/urfave/cli/v2"
+)
+
+// Command is the cancel subcommand.
+var Command = &cli.Command{
+	Name:         "cancel",
+	ArgsUsage:    "<workflow name> [job name]",
+	Aliases:      []string{"c"},
+	Usage:        "Cancel a workflow or job",
+	BashComplete: complete.WorkflowJob,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "all-running",
+			Usage: "Cancel every running workflow of the newest pipeline",
+		},
+	},
+	Action: action,
+}
+
+func action(c *cli.Context) error {
+	ctx, done := signal.InitContext()
+	defer done()
+
+	client, err := global.Client(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	if c.Bool("all-running") && c.NArg() > 0 {
+		return cli.NewExitError("cannot specify `<workflow name>` with --all-running", -1)
+	}
+
+	if !c.Bool("all-running") && (c.NArg() < 1 || c.NArg() > 2) {
+		return cli.NewExitError("must specify `<workflow name> [job name]` or --all-running", -1)
+	}
+
+	s, err := status.Check(ctx, client, global.Filter(c), 1)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	var res string
+
+	switch c.NArg() {
+	case 2:
+		job := s.Job(c.Args().Get(0), c.Args().Get(1))
+		if job == nil {
+			return cli.NewExitError("job not found", exit.CodeNotFound)
+		}
+		res, err = cancel.Job(ctx, client, job.Number)
+	case 1:
+		workflow := s.Workflow(c.Args().Get(0))
+		if workflow == nil {
+			return cli.NewExitError("workflow not found", exit.CodeNotFound)
+		}
+		res, err = cancel.Workflow(ctx, client, workflow.ID)
+	default:
+		var canceled []string
+		canceled, err = cancel.Running(ctx, client, s)
+		if len(canceled) > 0 {
+			res = fmt.Sprintf("canceled: %s", strings.Join(canceled, ", "))
+		} else if err == nil {
+			res = "no running workflows"
+		}
+	}
+
+	if res != "" {
+		fmt.Println(res)
+	}
+	if err != nil {
+		return exit.Error(err)
+	}
+	return nil
+}
@@ -17,7 +17,7 @@ func Job(c *cli.Context) {
 	ctx, cancel := signal.InitContext()
 	defer cancel()
 
-	client, err := global.Client(c)
+	client, err := global.CompletionClient(c)
 	if err != nil {
 		return
 	}
@@ -32,7 +32,7 @@ func Job(c *cli.Context) {
 		}
 
 		workflowName := c.Args().Get(0)
-		s, err := status.Check(ctx, client, c.String("branch"), 1)
+		s, err := status.Check(ctx, client, global.Filter(c), 1)
 		if err != nil {
 			return
 		}
@@ -45,7 +45,7 @@ func Job(c *cli.Context) {
 			fmt.Println(j.Name)
 		}
 	default:
-		s, err := status.Check(ctx, client, c.String("branch"), 1)
+		s, err := status.Check(ctx, client, global.Filter(c), 1)
 		if err != nil {
 			return
 		}
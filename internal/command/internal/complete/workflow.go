@@ -15,7 +15,7 @@ func Workflow(c *cli.Context) {
 	ctx, cancel := signal.InitContext()
 	defer cancel()
 
-	client, err := global.Client(c)
+	client, err := global.CompletionClient(c)
 	if err != nil {
 		return
 	}
@@ -24,7 +24,7 @@ func Workflow(c *cli.Context) {
 	case nargs >= 1:
 		return
 	default:
-		s, err := status.Check(ctx, client, c.String("branch"), 1)
+		s, err := status.Check(ctx, client, global.Filter(c), 1)
 		if err != nil {
 			return
 		}
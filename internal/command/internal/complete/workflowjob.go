@@ -0,0 +1,52 @@
+// Package complete provides functions for shell completion of subcommands.
+package complete
+
+import (
+	"fmt"
+
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/tmessi/cci/internal/status"
+	"github.com/urfave/cli/v2"
+)
+
+// WorkflowJob provides completion values for commands that take a workflow
+// name and optionally a job name.
+func WorkflowJob(c *cli.Context) {
+	ctx, cancel := signal.InitContext()
+	defer cancel()
+
+	client, err := global.CompletionClient(c)
+	if err != nil {
+		return
+	}
+
+	switch nargs := c.NArg(); {
+	case nargs >= 2:
+		return
+	case nargs == 1:
+		s, err := status.Check(ctx, client, global.Filter(c), 1)
+		if err != nil {
+			return
+		}
+		workflow := s.Workflow(c.Args().Get(0))
+		if workflow == nil {
+			return
+		}
+
+		for _, j := range workflow.Jobs {
+			fmt.Println(j.Name)
+		}
+	default:
+		s, err := status.Check(ctx, client, global.Filter(c), 1)
+		if err != nil {
+			return
+		}
+		if len(s.Pipelines) <= 0 {
+			return
+		}
+		for _, w := range s.Pipelines[0].Workflows {
+			fmt.Println(w.Name)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package exit maps errors to cli exit errors with a friendly message
+// and a distinct exit code.
+package exit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/status"
+	"github.com/urfave/cli/v2"
+)
+
+// Exit codes for the outcome of a pipeline. The codes for known API
+// errors are skipped so that scripts can tell them apart.
+const (
+	CodeSuccess  = 0
+	CodeFailed   = 1
+	CodeRunning  = 2
+	CodeOnHold   = 3
+	CodeCanceled = 7
+)
+
+// CodeInterrupted is the exit code when a command is interrupted,
+// e.g. by Ctrl-C, following the shell convention for SIGINT.
+const CodeInterrupted = 130
+
+// Interrupted is the cli.ExitCoder for an interrupted command.
+func Interrupted() cli.ExitCoder {
+	return cli.NewExitError("interrupted", CodeInterrupted)
+}
+
+// Exit codes for known API errors.
+const (
+	CodeError        = -1
+	CodeUnauthorized = 4
+	CodeNotFound     = 5
+	CodeRateLimited  = 6
+)
+
+var outcomeCodes = map[status.Outcome]int{
+	status.OutcomeSuccess:  CodeSuccess,
+	status.OutcomeFailed:   CodeFailed,
+	status.OutcomeRunning:  CodeRunning,
+	status.OutcomeOnHold:   CodeOnHold,
+	status.OutcomeCanceled: CodeCanceled,
+}
+
+// Outcome converts the outcome of a pipeline to a cli.ExitCoder.
+// It returns nil if the pipeline succeeded.
+func Outcome(o status.Outcome) error {
+	code := outcomeCodes[o]
+	if code == CodeSuccess {
+		return nil
+	}
+	return cli.NewExitError("", code)
+}
+
+// notFound describes what was not found. Many resources can be missing,
+// e.g. a project, job or artifact, so the message and url of the request
+// are included when known.
+func notFound(err error) string {
+	var apiErr *circleci.APIError
+	if !errors.As(err, &apiErr) {
+		return fmt.Sprintf("not found: %s", err)
+	}
+
+	msg := "not found"
+	if apiErr.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, apiErr.Message)
+	}
+	if apiErr.URL != "" {
+		msg = fmt.Sprintf("%s (%s %s)", msg, apiErr.Method, apiErr.URL)
+	}
+	return msg
+}
+
+// Error converts err to a cli.ExitCoder. Known CircleCI API errors are
+// reported with a short message and their own exit code.
+func Error(err error) cli.ExitCoder {
+	switch {
+	case errors.Is(err, circleci.ErrUnauthorized):
+		return cli.NewExitError("token invalid", CodeUnauthorized)
+	case errors.Is(err, circleci.ErrNotFound):
+		return cli.NewExitError(notFound(err), CodeNotFound)
+	case errors.Is(err, circleci.ErrRateLimited):
+		return cli.NewExitError("rate limited by CircleCI, try again later", CodeRateLimited)
+	default:
+		return cli.NewExitError(err.Error(), CodeError)
+	}
+}
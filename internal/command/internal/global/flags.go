@@ -4,20 +4,50 @@ package global
 import (
 	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/command/internal/global/internal/config"
 	"github.com/tmessi/cci/internal/command/internal/global/internal/git"
+	"github.com/tmessi/cci/internal/command/internal/prompt"
+	"github.com/tmessi/cci/internal/credential"
 	"github.com/urfave/cli/v2"
 )
 
 // Flags are the flags that apply to subcommands.
 var Flags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "config",
+		Usage:   "The path to the config file",
+		EnvVars: []string{"CCI_CONFIG"},
+		Value:   config.Path(),
+	},
+	&cli.StringFlag{
+		Name:    "profile",
+		Usage:   "The profile from the config file to use",
+		EnvVars: []string{"CCI_PROFILE"},
+	},
 	&cli.StringFlag{
 		Name:    "token",
 		Aliases: []string{"circleci-token"},
 		Usage:   "Token used for authenticating to the CircleCI API.",
 		EnvVars: []string{"CIRCLE_CI_TOKEN"},
 	},
+	&cli.StringFlag{
+		Name:    "credential-store",
+		Usage:   "Where tokens are stored by `cci auth login`: auto, keyring, file or helper",
+		EnvVars: []string{"CCI_CREDENTIAL_STORE"},
+		Value:   credential.KindAuto,
+	},
+	&cli.StringFlag{
+		Name:    "credential-helper",
+		Usage:   "The command used to get, store and erase tokens with the helper credential store",
+		EnvVars: []string{"CCI_CREDENTIAL_HELPER"},
+	},
 	&cli.StringFlag{
 		Name:    "vcs-type",
 		Usage:   "The vcs type for the project.",
@@ -56,6 +86,24 @@ var Flags = []cli.Flag{
 		Usage:   "The number of pipeline results to return",
 		Value:   1,
 	},
+	&cli.UintFlag{
+		Name:    "max-retries",
+		Usage:   "The number of times to retry a request that was rate limited, or a read that failed with a server error",
+		EnvVars: []string{"CCI_MAX_RETRIES"},
+		Value:   3,
+	},
+	&cli.DurationFlag{
+		Name:    "max-retry-wait",
+		Usage:   "The longest time to wait between retries of a request",
+		EnvVars: []string{"CCI_MAX_RETRY_WAIT"},
+		Value:   30 * time.Second,
+	},
+	&cli.IntFlag{
+		Name:    "concurrency",
+		Usage:   "The maximum number of concurrent requests to make to CircleCI",
+		EnvVars: []string{"CCI_CONCURRENCY"},
+		Value:   8,
+	},
 }
 
 // Errors for invalid flag values.
@@ -64,14 +112,268 @@ var (
 	ErrNoOrg     = errors.New("no organization specified")
 	ErrNoVCSType = errors.New("no vcs-type specified")
 	ErrNoToken   = errors.New("no circleci token specified")
+
+	ErrInvalidInterval   = errors.New("--interval must be greater than zero")
+	ErrTemplateAndFile   = errors.New("cannot specify both --template and --template-file")
+	ErrTemplateAndFormat = errors.New("cannot specify both --format and a template")
 )
 
+// profileFlags maps global flags to their value in a config.Profile.
+var profileFlags = map[string]func(*config.Profile) string{
+	"token":    func(p *config.Profile) string { return p.Token },
+	"url":      func(p *config.Profile) string { return p.URL },
+	"vcs-type": func(p *config.Profile) string { return p.VCSType },
+	"org":      func(p *config.Profile) string { return p.Org },
+}
+
+// loadConfig loads the config file and applies the selected profile, and
+// defaults, to any global flags that were not set by a flag or environment
+// variable.
+func loadConfig(c *cli.Context) error {
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	p, err := cfg.Select(c.String("profile"))
+	if err != nil {
+		return err
+	}
+
+	for name, value := range profileFlags {
+		if v := value(p); v != "" && !c.IsSet(name) {
+			if err := c.Set(name, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.CredentialStore != "" && !c.IsSet("credential-store") {
+		if err := c.Set("credential-store", cfg.CredentialStore); err != nil {
+			return err
+		}
+	}
+
+	if cfg.CredentialHelper != "" && !c.IsSet("credential-helper") {
+		if err := c.Set("credential-helper", cfg.CredentialHelper); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Defaults.Limit > 0 && !c.IsSet("limit") {
+		return c.Set("limit", strconv.FormatUint(cfg.Defaults.Limit, 10))
+	}
+	return nil
+}
+
+// passphrase returns the passphrase for the file credential store,
+// prompting for it if it is not in the environment.
+func passphrase() (string, error) {
+	if p := os.Getenv("CCI_CREDENTIAL_PASSPHRASE"); p != "" {
+		return p, nil
+	}
+	return prompt.Secret("Credential store passphrase: ")
+}
+
+// Credentials returns the credential.Store selected by the global flags.
+// The file store is kept next to the config file.
+func Credentials(c *cli.Context) (credential.Store, error) {
+	if err := loadConfig(c); err != nil {
+		return nil, err
+	}
+
+	return credential.New(&credential.Options{
+		Kind:       c.String("credential-store"),
+		Helper:     c.String("credential-helper"),
+		File:       filepath.Join(filepath.Dir(c.String("config")), "credentials"),
+		Passphrase: passphrase,
+	})
+}
+
+// Sources of a token.
+const (
+	TokenFromFlag    = "flag or environment"
+	TokenFromProfile = "config profile"
+)
+
+// Token returns the token for the CircleCI api and where it came from.
+// A token set by a flag or environment variable is used first, then one
+// from the config profile, then one from the credential store.
+func Token(c *cli.Context) (string, string, error) {
+	set := c.IsSet("token")
+
+	if err := loadConfig(c); err != nil {
+		return "", "", err
+	}
+
+	if token := c.String("token"); token != "" {
+		if set {
+			return token, TokenFromFlag, nil
+		}
+		return token, TokenFromProfile, nil
+	}
+
+	store, err := Credentials(c)
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := store.Get(c.String("url"))
+	if errors.Is(err, credential.ErrNotFound) {
+		return "", "", ErrNoToken
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return token, store.String(), nil
+}
+
+// UserClient creates a circleci.Client for the given token that is not
+// associated with a project. It can only be used for requests about the
+// user, like circleci.Client.Me.
+func UserClient(c *cli.Context, token string) (*circleci.Client, error) {
+	if err := loadConfig(c); err != nil {
+		return nil, err
+	}
+
+	return circleci.New(
+		&http.Client{},
+		c.String("url"),
+		nil,
+		token,
+		circleci.WithRetries(c.Uint("max-retries"), c.Duration("max-retry-wait")),
+	), nil
+}
+
+// Interval returns the value of the interval flag of commands that poll
+// CircleCI, ensuring it is positive.
+func Interval(c *cli.Context) (time.Duration, error) {
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return 0, ErrInvalidInterval
+	}
+	return interval, nil
+}
+
+// Format returns the output format for commands with a format flag.
+// If the flag was not set, the default from the config file is used.
+func Format(c *cli.Context) (string, error) {
+	if c.IsSet("format") {
+		return c.String("format"), nil
+	}
+
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return "", err
+	}
+	if cfg.Defaults.Format != "" {
+		return cfg.Defaults.Format, nil
+	}
+	return c.String("format"), nil
+}
+
+// BranchFlag returns a --branch flag for a subcommand. The global flags are
+// only parsed before the subcommand, so a subcommand that is commonly given a
+// branch declares its own, with the same default.
+func BranchFlag(usage string) cli.Flag {
+	return &cli.StringFlag{
+		Name:    "branch",
+		Aliases: []string{"b"},
+		Usage:   usage,
+		EnvVars: []string{"CCI_BRANCH"},
+		Value:   git.Defaults.Branch,
+	}
+}
+
+// Branch returns the value of the --branch flag, and whether it was set.
+// The flag of a subcommand takes precedence over the global flag.
+func Branch(c *cli.Context) (string, bool) {
+	for _, l := range c.Lineage() {
+		for _, name := range l.LocalFlagNames() {
+			if name == "branch" {
+				return l.String("branch"), true
+			}
+		}
+	}
+	return c.String("branch"), false
+}
+
+// Filter returns the filter for the pipelines to check, from the global
+// flags. If no branch is set because HEAD is detached, the pipelines of the
+// current commit are used instead.
+func Filter(c *cli.Context) *circleci.PipelineFilter {
+	branch := c.String("branch")
+	if branch == "" {
+		return &circleci.PipelineFilter{Revision: git.Defaults.Commit}
+	}
+	return &circleci.PipelineFilter{Branch: branch}
+}
+
+// Commit resolves a revision, like HEAD, to a commit SHA using the
+// git repository in the current working directory.
+func Commit(rev string) (string, error) {
+	return git.Resolve(rev)
+}
+
+// Template returns the user supplied template for the text output of a
+// command, from the --template or --template-file flags, or the selected
+// profile of the config file. An empty template means the built in text
+// output is used. format is the output format of the command as returned by
+// Format, or empty for commands without one. A template from the config file
+// is only used for text output.
+func Template(c *cli.Context, command, format string) (string, error) {
+	switch {
+	case c.IsSet("template") && c.IsSet("template-file"):
+		return "", ErrTemplateAndFile
+	case (c.IsSet("template") || c.IsSet("template-file")) && c.IsSet("format"):
+		return "", ErrTemplateAndFormat
+	case c.IsSet("template"):
+		return c.String("template"), nil
+	case c.IsSet("template-file"):
+		b, err := os.ReadFile(c.String("template-file"))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(b), "\n"), nil
+	case format != "" && format != "text":
+		return "", nil
+	}
+
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return "", err
+	}
+
+	p, err := cfg.Select(c.String("profile"))
+	if err != nil {
+		return "", err
+	}
+	return p.Templates[command], nil
+}
+
 // Client creates a circleci.Client from the global cli flags.
+// Flags that are not set are populated from the config file, and the
+// token from the credential store.
 func Client(c *cli.Context) (*circleci.Client, error) {
+	return client(c, true)
+}
+
+// CompletionClient creates a circleci.Client like Client, for shell
+// completion. It never uses the credential store, which may prompt for a
+// passphrase or be slow to respond, so it needs a token from a flag,
+// environment variable or the config profile.
+func CompletionClient(c *cli.Context) (*circleci.Client, error) {
+	return client(c, false)
+}
+
+func client(c *cli.Context, useStore bool) (*circleci.Client, error) {
+	if err := loadConfig(c); err != nil {
+		return nil, err
+	}
+
 	project := c.String("project")
 	org := c.String("org")
 	vcsType := c.String("vcs-type")
-	token := c.String("token")
 
 	if project == "" {
 		return nil, ErrNoProject
@@ -85,6 +387,17 @@ func Client(c *cli.Context) (*circleci.Client, error) {
 		return nil, ErrNoVCSType
 	}
 
+	// The credential store is only consulted once the project is known,
+	// and if no token was set some other way.
+	token := c.String("token")
+	if token == "" && useStore {
+		var err error
+		token, _, err = Token(c)
+		if err != nil && !errors.Is(err, ErrNoToken) {
+			return nil, err
+		}
+	}
+
 	if token == "" {
 		return nil, ErrNoToken
 	}
@@ -98,5 +411,7 @@ func Client(c *cli.Context) (*circleci.Client, error) {
 			VCSType:      vcsType,
 		},
 		token,
+		circleci.WithRetries(c.Uint("max-retries"), c.Duration("max-retry-wait")),
+		circleci.WithConcurrency(c.Int("concurrency")),
 	), nil
 }
@@ -0,0 +1,185 @@
+package global_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/urfave/cli/v2"
+)
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults string
+		args     []string
+		expected string
+		err      error
+	}{
+		{"Profile", "", nil, "{{ .Outcome }}", nil},
+		{"ProfileDefaultText", "text", nil, "{{ .Outcome }}", nil},
+		{"ProfileFormatText", "", []string{"--format", "text"}, "{{ .Outcome }}", nil},
+		{"DefaultFormatJSON", "json", nil, "", nil},
+		{"FormatYAML", "", []string{"--format", "yaml"}, "", nil},
+		{"Flag", "json", []string{"--template", "{{ .Number }}"}, "{{ .Number }}", nil},
+		{"FlagAndFormat", "", []string{"--format", "json", "--template", "{{ .Number }}"}, "", global.ErrTemplateAndFormat},
+		{"FlagAndFile", "", []string{"--template", "a", "--template-file", "b"}, "", global.ErrTemplateAndFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			contents := "defaults:\n  format: " + tt.defaults + "\n" +
+				"profiles:\n  default:\n    templates:\n      status: '{{ .Outcome }}'\n"
+			if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			var got string
+			var err error
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "config"},
+					&cli.StringFlag{Name: "profile"},
+					&cli.StringFlag{Name: "format"},
+					&cli.StringFlag{Name: "template"},
+					&cli.StringFlag{Name: "template-file"},
+				},
+				Action: func(c *cli.Context) error {
+					format, ferr := global.Format(c)
+					if ferr != nil {
+						return ferr
+					}
+					got, err = global.Template(c, "status", format)
+					return nil
+				},
+			}
+
+			args := append([]string{"cci", "--config", path}, tt.args...)
+			if runErr := app.Run(args); runErr != nil {
+				t.Fatalf("err: %s", runErr.Error())
+			}
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if got != tt.expected {
+				t.Errorf("got %q, wanted %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		completion bool
+		err        error
+		helperRan  bool
+	}{
+		{"Store", []string{"--project", "cci"}, false, nil, true},
+		{"Token", []string{"--project", "cci", "--token", "valid-token"}, false, nil, false},
+		{"NoProject", nil, false, global.ErrNoProject, false},
+		{"Completion", []string{"--project", "cci"}, true, global.ErrNoToken, false},
+		{"CompletionToken", []string{"--project", "cci", "--token", "valid-token"}, true, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			ran := filepath.Join(dir, "ran")
+
+			var err error
+			app := &cli.App{
+				Flags: global.Flags,
+				Action: func(c *cli.Context) error {
+					if tt.completion {
+						_, err = global.CompletionClient(c)
+					} else {
+						_, err = global.Client(c)
+					}
+					return nil
+				},
+			}
+
+			args := append([]string{
+				"cci",
+				"--config", filepath.Join(dir, "config.yaml"),
+				"--credential-store", "helper",
+				"--credential-helper", "f() { touch " + ran + "; echo token=stored-token; }; f",
+				"--org", "tmessi",
+				"--vcs-type", "github",
+				"--project", "",
+			}, tt.args...)
+			t.Setenv("CIRCLE_CI_TOKEN", "")
+			if runErr := app.Run(args); runErr != nil {
+				t.Fatalf("err: %s", runErr.Error())
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got %v, wanted %v", err, tt.err)
+			}
+
+			_, statErr := os.Stat(ran)
+			if helperRan := statErr == nil; helperRan != tt.helperRan {
+				t.Errorf("helper ran: got %t, wanted %t", helperRan, tt.helperRan)
+			}
+		})
+	}
+}
+
+func TestBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		set      bool
+	}{
+		{"Default", []string{"cci", "trigger"}, "detected", false},
+		{"Subcommand", []string{"cci", "trigger", "--branch", "main"}, "main", true},
+		{"SubcommandAlias", []string{"cci", "trigger", "-b", "main"}, "main", true},
+		{"Global", []string{"cci", "--branch", "dev", "trigger"}, "dev", true},
+		{"Both", []string{"cci", "--branch", "dev", "trigger", "--branch", "main"}, "main", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var set bool
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "branch", Value: "detected"},
+				},
+				Commands: []*cli.Command{
+					{
+						Name:  "trigger",
+						Flags: []cli.Flag{&cli.StringFlag{Name: "branch", Aliases: []string{"b"}, Value: "detected"}},
+						Action: func(c *cli.Context) error {
+							got, set = global.Branch(c)
+							return nil
+						},
+					},
+				},
+			}
+
+			if err := app.Run(tt.args); err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if got != tt.expected || set != tt.set {
+				t.Errorf("got %q, %t, wanted %q, %t", got, set, tt.expected, tt.set)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package config provides values for global flags from a config file.
+// The config file holds named profiles, allowing for switching between
+// CircleCI organizations or servers without juggling environment variables.
+//
+// An example config file:
+//
+//	profile: work
+//	credential-store: keyring
+//	defaults:
+//	  limit: 3
+//	  format: text
+//	profiles:
+//	  work:
+//	    token: <personal access token>
+//	    org: my-org
+//	    templates:
+//	      status: '{{ .Outcome }}'
+//	  server:
+//	    token: <personal access token>
+//	    url: https://circleci.example.com
+//	    vcs-type: github
+//	    org: my-org
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Known errors.
+var (
+	ErrNoProfile = errors.New("profile not found")
+)
+
+// DefaultProfile is the profile used if none is selected,
+// and the config file does not specify one.
+const DefaultProfile = "default"
+
+// Profile holds the values for connecting to a CircleCI organization.
+type Profile struct {
+	Token   string `yaml:"token"`
+	URL     string `yaml:"url"`
+	VCSType string `yaml:"vcs-type"`
+	Org     string `yaml:"org"`
+	// Templates are the default templates for rendering the text
+	// output of a subcommand, keyed by the name of the subcommand.
+	Templates map[string]string `yaml:"templates"`
+}
+
+// Defaults are default values for flags of subcommands.
+type Defaults struct {
+	Limit  uint64 `yaml:"limit"`
+	Format string `yaml:"format"`
+}
+
+// Config is the contents of the config file.
+type Config struct {
+	// Profile is the name of the profile to use if none is selected.
+	Profile string `yaml:"profile"`
+	// CredentialStore is the kind of store used for tokens that are
+	// not in a profile.
+	CredentialStore string `yaml:"credential-store"`
+	// CredentialHelper is the command used by a helper credential store.
+	CredentialHelper string `yaml:"credential-helper"`
+
+	Defaults Defaults            `yaml:"defaults"`
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// Path returns the default path of the config file. It respects
+// XDG_CONFIG_HOME, falling back to ~/.config.
+func Path() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "cci", "config.yaml")
+}
+
+// Load reads the config file at path. A missing file is not an error,
+// an empty Config is returned instead.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Select returns the profile with the given name. If name is empty, the
+// profile named by the config file is used, or DefaultProfile. It is only
+// an error if a profile was asked for by name and does not exist.
+func (c *Config) Select(name string) (*Profile, error) {
+	explicit := name != ""
+	if !explicit {
+		name = c.Profile
+		explicit = name != ""
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		if explicit {
+			return nil, fmt.Errorf("%w: %s", ErrNoProfile, name)
+		}
+		return &Profile{}, nil
+	}
+	return p, nil
+}
@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tmessi/cci/internal/command/internal/global/internal/config"
+)
+
+const testConfig = `
+profile: cloud
+credential-store: helper
+credential-helper: pass-cci
+defaults:
+  limit: 5
+  format: json
+profiles:
+  cloud:
+    token: cloud-token
+    org: tmessi
+    templates:
+      status: '{{ .Outcome }}'
+  server:
+    token: server-token
+    url: https://circleci.example.com
+    vcs-type: github
+    org: example
+`
+
+func TestPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+
+	if got, want := config.Path(), filepath.Join("/xdg", "cci", "config.yaml"); got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatalf("test not configured correctly: %s", err.Error())
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if cfg.Defaults.Limit != 5 {
+		t.Errorf("Limit: got %d, wanted %d", cfg.Defaults.Limit, 5)
+	}
+
+	if cfg.Defaults.Format != "json" {
+		t.Errorf("Format: got %q, wanted %q", cfg.Defaults.Format, "json")
+	}
+
+	if cfg.CredentialStore != "helper" {
+		t.Errorf("CredentialStore: got %q, wanted %q", cfg.CredentialStore, "helper")
+	}
+
+	if cfg.CredentialHelper != "pass-cci" {
+		t.Errorf("CredentialHelper: got %q, wanted %q", cfg.CredentialHelper, "pass-cci")
+	}
+
+	tests := []struct {
+		name     string
+		profile  string
+		expected *config.Profile
+		err      error
+	}{
+		{
+			"ConfigDefault",
+			"",
+			&config.Profile{Token: "cloud-token", Org: "tmessi", Templates: map[string]string{"status": "{{ .Outcome }}"}},
+			nil,
+		},
+		{
+			"Named",
+			"server",
+			&config.Profile{Token: "server-token", URL: "https://circleci.example.com", VCSType: "github", Org: "example"},
+			nil,
+		},
+		{
+			"Missing",
+			"missing",
+			nil,
+			config.ErrNoProfile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := cfg.Select(tt.profile)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(p, tt.expected) {
+				t.Errorf("got %+v, wanted %+v", p, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	p, err := cfg.Select("")
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(p, &config.Profile{}) {
+		t.Errorf("got %+v, wanted empty profile", p)
+	}
+}
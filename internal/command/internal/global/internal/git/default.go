@@ -3,13 +3,26 @@
 // sane defaults if cci is run from within a git repository.
 // It will allow cci to check CircleCI for the current branch of
 // the repo in the current working directory.
+//
+// The remote used is, in order of preference, the one set by the
+// cci.remote git config key, the remote tracked by the current branch,
+// or origin. The vcs type is determined by the host of the remote.
+// Self-hosted servers can be mapped to a vcs type with git config:
+//
+//	git config --global cci.bitbucket.example.com.vcs-type bitbucket
+//
+// GitLab projects are not detected, since CircleCI identifies them by the
+// ids of the organization and project rather than their path.
 package git
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 
 	giturls "github.com/whilp/git-urls"
 )
@@ -19,6 +32,8 @@ type defaults struct {
 	Organization string
 	Repository   string
 	Branch       string
+	// Commit is the SHA of HEAD if it is detached, i.e. not on a branch.
+	Commit string
 }
 
 // Defaults provide default values for some global flags.
@@ -26,53 +41,173 @@ type defaults struct {
 // if run from within a git repository.
 var Defaults = &defaults{}
 
-func init() {
+// defaultRemote is the remote used if no other is configured.
+const defaultRemote = "origin"
+
+// vcsTypes maps well known hosts to their vcs type. An empty vcs type
+// means the project cannot be determined from the remote.
+var vcsTypes = map[string]string{
+	"github.com":    "github",
+	"bitbucket.org": "bitbucket",
+	"gitlab.com":    "",
+}
+
+// vcsTypeCircleCI is the vcs type of projects, like those on GitLab, that
+// are identified by ids, i.e. circleci/<org-id>/<project-id>.
+const vcsTypeCircleCI = "circleci"
+
+// option returns the first value of the key found in configs.
+func option(configs []*config.Config, subsection, key string) string {
+	for _, c := range configs {
+		s := c.Raw.Section("cci")
+		if subsection != "" {
+			if !s.HasSubsection(subsection) {
+				continue
+			}
+			if v := s.Subsection(subsection).Option(key); v != "" {
+				return v
+			}
+			continue
+		}
+		if v := s.Option(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// remoteName returns the name of the remote to use for the project.
+func remoteName(configs []*config.Config, local *config.Config, branch string) string {
+	if r := option(configs, "", "remote"); r != "" {
+		return r
+	}
+
+	// A remote of "." means the branch tracks another local branch.
+	if b, ok := local.Branches[branch]; ok && b.Remote != "" && b.Remote != "." {
+		return b.Remote
+	}
+
+	return defaultRemote
+}
+
+// vcsType returns the vcs type for the host of a remote.
+func vcsType(configs []*config.Config, host string) string {
+	if t := option(configs, host, "vcs-type"); t != "" {
+		return t
+	}
+
+	if t, ok := vcsTypes[host]; ok {
+		return t
+	}
+	return "github"
+}
+
+// open opens the repository containing the current working directory.
+func open() (*git.Repository, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	repo, err := git.PlainOpenWithOptions(
+	return git.PlainOpenWithOptions(
 		cwd,
 		&git.PlainOpenOptions{DetectDotGit: true},
 	)
+}
+
+// isSHA reports whether rev looks like a, possibly abbreviated, commit SHA.
+func isSHA(rev string) bool {
+	return len(rev) >= 4 && len(rev) <= 40 && strings.Trim(rev, "0123456789abcdef") == ""
+}
+
+// Resolve resolves a revision, like HEAD, a branch or a tag, to the SHA of
+// its commit using the repository in the current working directory. A
+// revision that cannot be resolved but looks like a SHA is returned as is,
+// since the commit may not have been fetched.
+func Resolve(rev string) (string, error) {
+	repo, err := open()
 	if err != nil {
-		return
+		if isSHA(rev) {
+			return rev, nil
+		}
+		return "", err
 	}
+	return resolve(repo, rev)
+}
 
-	remote, err := repo.Remote("origin")
+func resolve(repo *git.Repository, rev string) (string, error) {
+	h, err := repo.ResolveRevision(plumbing.Revision(rev))
 	if err != nil {
-		return
+		if isSHA(rev) {
+			return rev, nil
+		}
+		return "", fmt.Errorf("%s: %w", rev, err)
 	}
+	return h.String(), nil
+}
+
+// load returns the defaults for repo. scoped are the global and system git
+// configs, with the most specific first.
+func load(repo *git.Repository, scoped []*config.Config) *defaults {
+	d := &defaults{}
 
-	remoteURL := remote.Config().URLs[0]
-	u, err := giturls.Parse(remoteURL)
+	head, err := repo.Head()
+	if err == nil {
+		if head.Name().IsBranch() {
+			d.Branch = head.Name().Short()
+		} else {
+			d.Commit = head.Hash().String()
+		}
+	}
+
+	local, err := repo.Config()
 	if err != nil {
-		return
+		return d
 	}
+	configs := append([]*config.Config{local}, scoped...)
 
-	switch u.Host {
-	case "bitbucket.org":
-		Defaults.Type = "bitbucket"
-	case "github.com":
-		fallthrough
-	default:
-		Defaults.Type = "github"
+	remote, err := repo.Remote(remoteName(configs, local, d.Branch))
+	if err != nil {
+		return d
 	}
 
-	parts := strings.SplitN(u.Path, "/", 2)
-	if len(parts) != 2 {
-		return
+	u, err := giturls.Parse(remote.Config().URLs[0])
+	if err != nil {
+		return d
+	}
+
+	d.Type = vcsType(configs, u.Hostname())
+	if d.Type == "" || d.Type == vcsTypeCircleCI {
+		// The ids are not part of the remote, so they must be set
+		// with flags, environment variables or the config file.
+		return d
 	}
-	Defaults.Organization = parts[0]
-	Defaults.Repository = strings.TrimSuffix(parts[1], ".git")
 
-	ref, err := repo.Head()
+	// The organization may contain slashes, e.g. for nested groups.
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return d
+	}
+	d.Organization = path[:i]
+	d.Repository = path[i+1:]
+	return d
+}
+
+func init() {
+	repo, err := open()
 	if err != nil {
 		return
 	}
 
-	if ref.Name().IsBranch() {
-		Defaults.Branch = ref.Name().Short()
+	// The raw cci section is not merged by repo.ConfigScoped,
+	// so each scope is checked, with the most specific first.
+	var scoped []*config.Config
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		if c, err := config.LoadConfig(scope); err == nil {
+			scoped = append(scoped, c)
+		}
 	}
+
+	Defaults = load(repo, scoped)
 }
@@ -0,0 +1,273 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// testRepo creates an in-memory repository with a single commit on the
+// main branch, and the given remotes.
+func testRepo(t *testing.T, remotes map[string]string) (*git.Repository, plumbing.Hash) {
+	t.Helper()
+
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	tree := repo.Storer.NewEncodedObject()
+	if err := (&object.Tree{}).Encode(tree); err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+	treeHash, err := repo.Storer.SetEncodedObject(tree)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	sig := object.Signature{Name: "cci", Email: "cci@example.com", When: time.Unix(0, 0)}
+	commit := repo.Storer.NewEncodedObject()
+	err = (&object.Commit{Author: sig, Committer: sig, Message: "initial", TreeHash: treeHash}).Encode(commit)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+	hash, err := repo.Storer.SetEncodedObject(commit)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	main := plumbing.NewBranchReferenceName("main")
+	for _, ref := range []*plumbing.Reference{
+		plumbing.NewHashReference(main, hash),
+		plumbing.NewSymbolicReference(plumbing.HEAD, main),
+	} {
+		if err := repo.Storer.SetReference(ref); err != nil {
+			t.Fatalf("err: %s", err.Error())
+		}
+	}
+
+	for name, url := range remotes {
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+			t.Fatalf("err: %s", err.Error())
+		}
+	}
+	return repo, hash
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		remotes  map[string]string
+		local    func(*config.Config)
+		global   func(*config.Config)
+		detached bool
+		expected defaults
+	}{
+		{
+			"Origin",
+			map[string]string{"origin": "git@github.com:tmessi/cci.git"},
+			nil,
+			nil,
+			false,
+			defaults{Type: "github", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"HTTPS",
+			map[string]string{"origin": "https://bitbucket.org/tmessi/cci"},
+			nil,
+			nil,
+			false,
+			defaults{Type: "bitbucket", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"NestedGroup",
+			map[string]string{"origin": "git@git.example.com:tmessi/tools/cci.git"},
+			nil,
+			nil,
+			false,
+			defaults{Type: "github", Organization: "tmessi/tools", Repository: "cci", Branch: "main"},
+		},
+		{
+			"GitLab",
+			map[string]string{"origin": "git@gitlab.com:tmessi/tools/cci.git"},
+			nil,
+			nil,
+			false,
+			defaults{Branch: "main"},
+		},
+		{
+			"CircleCIType",
+			map[string]string{"origin": "git@gitlab.example.com:tmessi/cci.git"},
+			nil,
+			func(c *config.Config) {
+				c.Raw.Section("cci").Subsection("gitlab.example.com").SetOption("vcs-type", "circleci")
+			},
+			false,
+			defaults{Type: "circleci", Branch: "main"},
+		},
+		{
+			"TrackingRemote",
+			map[string]string{
+				"origin":   "git@github.com:someone/cci.git",
+				"upstream": "git@github.com:tmessi/cci.git",
+			},
+			func(c *config.Config) {
+				c.Branches["main"] = &config.Branch{Name: "main", Remote: "upstream", Merge: "refs/heads/main"}
+			},
+			nil,
+			false,
+			defaults{Type: "github", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"TrackingLocalBranch",
+			map[string]string{"origin": "git@github.com:tmessi/cci.git"},
+			func(c *config.Config) {
+				c.Branches["main"] = &config.Branch{Name: "main", Remote: ".", Merge: "refs/heads/dev"}
+			},
+			nil,
+			false,
+			defaults{Type: "github", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"ConfigRemote",
+			map[string]string{
+				"origin":   "git@github.com:someone/cci.git",
+				"upstream": "git@github.com:tmessi/cci.git",
+			},
+			func(c *config.Config) {
+				c.Branches["main"] = &config.Branch{Name: "main", Remote: "origin", Merge: "refs/heads/main"}
+				c.Raw.Section("cci").SetOption("remote", "upstream")
+			},
+			nil,
+			false,
+			defaults{Type: "github", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"SelfHosted",
+			map[string]string{"origin": "git@bitbucket.example.com:tmessi/cci.git"},
+			nil,
+			func(c *config.Config) {
+				c.Raw.Section("cci").Subsection("bitbucket.example.com").SetOption("vcs-type", "bitbucket")
+			},
+			false,
+			defaults{Type: "bitbucket", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"SelfHostedLocalOverride",
+			map[string]string{"origin": "git@git.example.com:tmessi/cci.git"},
+			func(c *config.Config) {
+				c.Raw.Section("cci").Subsection("git.example.com").SetOption("vcs-type", "bitbucket")
+			},
+			func(c *config.Config) {
+				c.Raw.Section("cci").Subsection("git.example.com").SetOption("vcs-type", "github")
+			},
+			false,
+			defaults{Type: "bitbucket", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"UnknownHost",
+			map[string]string{"origin": "git@git.example.com:tmessi/cci.git"},
+			nil,
+			nil,
+			false,
+			defaults{Type: "github", Organization: "tmessi", Repository: "cci", Branch: "main"},
+		},
+		{
+			"NoRemote",
+			nil,
+			nil,
+			nil,
+			false,
+			defaults{Branch: "main"},
+		},
+		{
+			"Detached",
+			map[string]string{"origin": "git@github.com:tmessi/cci.git"},
+			nil,
+			nil,
+			true,
+			defaults{Type: "github", Organization: "tmessi", Repository: "cci"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, hash := testRepo(t, tt.remotes)
+
+			if tt.local != nil {
+				c, err := repo.Config()
+				if err != nil {
+					t.Fatalf("err: %s", err.Error())
+				}
+				tt.local(c)
+				if err := repo.SetConfig(c); err != nil {
+					t.Fatalf("err: %s", err.Error())
+				}
+			}
+
+			var scoped []*config.Config
+			if tt.global != nil {
+				c := config.NewConfig()
+				tt.global(c)
+				scoped = append(scoped, c)
+			}
+
+			expected := tt.expected
+			if tt.detached {
+				if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, hash)); err != nil {
+					t.Fatalf("err: %s", err.Error())
+				}
+				expected.Commit = hash.String()
+			}
+
+			if got := load(repo, scoped); *got != expected {
+				t.Errorf("got %+v, wanted %+v", *got, expected)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	repo, hash := testRepo(t, nil)
+
+	tests := []struct {
+		name     string
+		rev      string
+		expected string
+		err      bool
+	}{
+		{"HEAD", "HEAD", hash.String(), false},
+		{"Branch", "main", hash.String(), false},
+		{"FullSHA", hash.String(), hash.String(), false},
+		{"UnknownSHA", "4f2a9c1", "4f2a9c1", false},
+		{"UnknownOddSHA", "4f2a9", "4f2a9", false},
+		{"TooShort", "4f2", "", true},
+		{"NotHex", "v1.0.0", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolve(repo, tt.rev)
+
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, but did not get one")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if got != tt.expected {
+				t.Errorf("got %q, wanted %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,25 +2,58 @@
 package output
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"os"
+	"time"
 
 	"github.com/tmessi/cci/internal/command/internal/complete"
+	"github.com/tmessi/cci/internal/command/internal/exit"
 	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/resolve"
 	"github.com/tmessi/cci/internal/command/internal/signal"
 	"github.com/tmessi/cci/internal/output"
-	"github.com/tmessi/cci/internal/status"
 	"github.com/urfave/cli/v2"
 )
 
 // Command is the output subcommand.
 var Command = &cli.Command{
 	Name:         "output",
-	ArgsUsage:    "<job number> | <workflow name> <job name>",
+	ArgsUsage:    resolve.JobUsage,
 	Aliases:      []string{"out", "o"},
 	Usage:        "Show output of a job",
 	BashComplete: complete.Job,
-	Action:       action,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "follow",
+			Aliases: []string{"f"},
+			Usage:   "Stream the output of a running job until it finishes",
+		},
+		&cli.Uint64Flag{
+			Name:    "container",
+			Aliases: []string{"c"},
+			Usage:   "Only show the output of the parallel container with this index",
+		},
+		&cli.BoolFlag{
+			Name:  "failed-only",
+			Usage: "Only show the output of steps that failed",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "How often to check for new output when following",
+			Value: 5 * time.Second,
+		},
+		&cli.StringFlag{
+			Name:  "template",
+			Usage: "Render the output with a Go template, see the README for the data and functions available",
+		},
+		&cli.StringFlag{
+			Name:  "template-file",
+			Usage: "Render the output with a Go template read from a file",
+		},
+	},
+	Action: action,
 }
 
 func action(c *cli.Context) error {
@@ -32,37 +65,62 @@ func action(c *cli.Context) error {
 		return cli.NewExitError(err.Error(), -1)
 	}
 
-	var n uint64
+	text, err := global.Template(c, "output", "")
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	n, err := resolve.Job(ctx, c, client)
+	if err != nil {
+		return err
+	}
 
-	switch c.NArg() {
-	case 2:
-		workflowName := c.Args().Get(0)
-		jobName := c.Args().Get(1)
+	opts := &output.Options{
+		FailedOnly:  c.Bool("failed-only"),
+		Concurrency: client.Concurrency(),
+	}
+	if c.IsSet("container") {
+		container := c.Uint64("container")
+		opts.Container = &container
+	}
 
-		s, err := status.Check(ctx, client, c.String("branch"), 1)
+	if c.Bool("follow") {
+		interval, err := global.Interval(c)
 		if err != nil {
 			return cli.NewExitError(err.Error(), -1)
 		}
-		job := s.Job(workflowName, jobName)
-		if job == nil {
-			return cli.NewExitError("job not found", 404)
+
+		// The output is streamed as it is produced, so there is
+		// never a complete Build to render with a template. A
+		// template from the config profile is ignored.
+		if c.IsSet("template") || c.IsSet("template-file") {
+			return cli.NewExitError("cannot use a template with --follow", -1)
+		}
+
+		err = output.Follow(ctx, client, n, opts, interval, os.Stdout)
+		if errors.Is(err, context.Canceled) {
+			return exit.Interrupted()
 		}
-		n = job.Number
-	case 1:
-		strconv.Atoi(c.Args().Get(0))
-		jobNum, err := strconv.Atoi(c.Args().Get(0))
 		if err != nil {
-			return cli.NewExitError("job number must be an int", -1)
+			return exit.Error(err)
 		}
-		n = uint64(jobNum)
-	default:
-		return cli.NewExitError("must specify `<job number>` or `<workflow name> <job name>`", -1)
+		return nil
+	}
+
+	b, err := output.GetBuild(ctx, client, n, opts)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	if text == "" {
+		fmt.Println(b)
+		return nil
 	}
 
-	b, err := output.GetBuild(ctx, client, n)
+	out, err := b.Template(text)
 	if err != nil {
 		return cli.NewExitError(err.Error(), -1)
 	}
-	fmt.Println(b)
+	fmt.Println(out)
 	return nil
 }
@@ -0,0 +1,51 @@
+// Package prompt reads input from the user.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Known errors.
+var (
+	ErrEmpty = errors.New("no input provided")
+)
+
+// stdin is shared by every prompt, so that a line buffered by one prompt
+// is still available to the next when input is piped in.
+var stdin = bufio.NewReader(os.Stdin)
+
+// Secret prompts for a value without echoing it to the terminal.
+// If stdin is not a terminal, a single line is read from it instead,
+// allowing the value to be piped in.
+func Secret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	var value string
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		value = string(b)
+	} else {
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return "", ErrEmpty
+		}
+		value = line
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", ErrEmpty
+	}
+	return value, nil
+}
@@ -0,0 +1,32 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestSecretPiped(t *testing.T) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		t.Skip("stdin is a terminal")
+	}
+	stdin = bufio.NewReader(strings.NewReader("valid-token\nsecret\n"))
+
+	for _, want := range []string{"valid-token", "secret"} {
+		got, err := Secret("")
+		if err != nil {
+			t.Fatalf("err: %s", err.Error())
+		}
+		if got != want {
+			t.Errorf("got %q, wanted %q", got, want)
+		}
+	}
+
+	if _, err := Secret(""); !errors.Is(err, ErrEmpty) {
+		t.Errorf("got %v, wanted %v", err, ErrEmpty)
+	}
+}
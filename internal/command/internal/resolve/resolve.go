@@ -0,0 +1,46 @@
+// Package resolve is used to resolve subcommand arguments to CircleCI resources.
+package resolve
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/status"
+	"github.com/urfave/cli/v2"
+)
+
+// JobUsage is the ArgsUsage for commands that resolve a job with Job.
+const JobUsage = "<job number> | <workflow name> <job name>"
+
+// Job resolves the job number from the arguments, which are either
+// a job number, or a workflow name and job name. The names are looked
+// up in the newest pipeline of the branch, or commit. The returned error is
+// suitable to return from a cli.ActionFunc.
+func Job(ctx context.Context, c *cli.Context, client *circleci.Client) (uint64, error) {
+	switch c.NArg() {
+	case 2:
+		workflowName := c.Args().Get(0)
+		jobName := c.Args().Get(1)
+
+		s, err := status.Check(ctx, client, global.Filter(c), 1)
+		if err != nil {
+			return 0, exit.Error(err)
+		}
+		job := s.Job(workflowName, jobName)
+		if job == nil {
+			return 0, cli.NewExitError("job not found", exit.CodeNotFound)
+		}
+		return job.Number, nil
+	case 1:
+		jobNum, err := strconv.Atoi(c.Args().Get(0))
+		if err != nil {
+			return 0, cli.NewExitError("job number must be an int", -1)
+		}
+		return uint64(jobNum), nil
+	default:
+		return 0, cli.NewExitError("must specify `<job number>` or `<workflow name> <job name>`", -1)
+	}
+}
@@ -2,9 +2,13 @@
 package retry
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/tmessi/cci/internal/circleci"
 	"github.com/tmessi/cci/internal/command/internal/complete"
+	"github.com/tmessi/cci/internal/command/internal/exit"
 	"github.com/tmessi/cci/internal/command/internal/global"
 	"github.com/tmessi/cci/internal/command/internal/signal"
 	"github.com/tmessi/cci/internal/retry"
@@ -15,11 +19,36 @@ import (
 // Command is the retry subcommand.
 var Command = &cli.Command{
 	Name:         "retry",
-	ArgsUsage:    "<workflow name>",
+	ArgsUsage:    "<workflow name> | --ssh <workflow name> <job name>",
 	Aliases:      []string{"r"},
 	Usage:        "Retry a build",
-	BashComplete: complete.Workflow,
-	Action:       action,
+	BashComplete: complete.WorkflowJob,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "from-failed",
+			Usage: "Only retry the failed jobs of the workflow",
+		},
+		&cli.StringSliceFlag{
+			Name:    "job",
+			Aliases: []string{"j"},
+			Usage:   "Only retry the job with this name, can be repeated",
+		},
+		&cli.BoolFlag{
+			Name:  "ssh",
+			Usage: "Retry a single job with SSH enabled and print the command to connect to it",
+		},
+		&cli.BoolFlag{
+			Name:    "wait",
+			Aliases: []string{"w"},
+			Usage:   "Wait for the retried workflow to finish and report its status",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "How often to check the retried workflow with --wait or --ssh",
+			Value: 5 * time.Second,
+		},
+	},
+	Action: action,
 }
 
 func action(c *cli.Context) error {
@@ -31,29 +60,84 @@ func action(c *cli.Context) error {
 		return cli.NewExitError(err.Error(), -1)
 	}
 
-	var workflowID string
+	if c.Bool("ssh") {
+		return ssh(ctx, c, client)
+	}
+
+	// Check the interval before retrying, rather than after.
+	var interval time.Duration
+	if c.Bool("wait") {
+		interval, err = global.Interval(c)
+		if err != nil {
+			return cli.NewExitError(err.Error(), -1)
+		}
+	}
+
+	var s *status.Status
+	var workflow *circleci.Workflow
 
 	switch c.NArg() {
 	case 1:
 		workflowName := c.Args().Get(0)
 
-		s, err := status.Check(ctx, client, c.String("branch"), 1)
+		s, err = status.Check(ctx, client, global.Filter(c), 1)
 		if err != nil {
-			return cli.NewExitError(err.Error(), -1)
+			return exit.Error(err)
 		}
-		workflow := s.Workflow(workflowName)
+		workflow = s.Workflow(workflowName)
 		if workflow == nil {
-			return cli.NewExitError("workflow not found", 404)
+			return cli.NewExitError("workflow not found", exit.CodeNotFound)
 		}
-		workflowID = workflow.ID
 	default:
 		return cli.NewExitError("must specify `<workflow name>`", -1)
 	}
 
-	res, err := retry.Workflow(ctx, client, workflowID)
+	res, err := retry.Workflow(ctx, client, workflow, &retry.Options{
+		FromFailed: c.Bool("from-failed"),
+		Jobs:       c.StringSlice("job"),
+	})
+	if err != nil {
+		return exit.Error(err)
+	}
+	fmt.Println(res)
+
+	if c.Bool("wait") {
+		ws, err := retry.Wait(ctx, client, s.Pipelines[0], workflow, res, interval)
+		if err != nil {
+			return exit.Error(err)
+		}
+		fmt.Println(ws)
+		return exit.Outcome(ws.Outcome())
+	}
+	return nil
+}
+
+func ssh(ctx context.Context, c *cli.Context, client *circleci.Client) error {
+	if c.NArg() != 2 {
+		return cli.NewExitError("must specify `<workflow name> <job name>` with --ssh", -1)
+	}
+
+	interval, err := global.Interval(c)
 	if err != nil {
 		return cli.NewExitError(err.Error(), -1)
 	}
+
+	workflowName := c.Args().Get(0)
+	jobName := c.Args().Get(1)
+
+	s, err := status.Check(ctx, client, global.Filter(c), 1)
+	if err != nil {
+		return exit.Error(err)
+	}
+	workflow := s.Workflow(workflowName)
+	if workflow == nil {
+		return cli.NewExitError("workflow not found", exit.CodeNotFound)
+	}
+
+	res, err := retry.SSH(ctx, client, s.Pipelines[0], workflow, jobName, interval)
+	if err != nil {
+		return exit.Error(err)
+	}
 	fmt.Println(res)
 	return nil
 }
@@ -2,20 +2,66 @@
 package status
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/command/internal/exit"
 	"github.com/tmessi/cci/internal/command/internal/global"
 	"github.com/tmessi/cci/internal/command/internal/signal"
 	"github.com/tmessi/cci/internal/status"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
 // Command is the status subcommand.
 var Command = &cli.Command{
 	Name:    "status",
 	Aliases: []string{"branch-status", "s"},
-	Usage:   "Show the status of a branch",
-	Action:  action,
+	Usage:   "Show the status of a branch, commit or tag",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "The output format, one of: text, json, yaml",
+			Value:   status.FormatText,
+		},
+		&cli.BoolFlag{
+			Name:    "watch",
+			Aliases: []string{"w"},
+			Usage:   "Refresh the status until the newest pipeline is done",
+		},
+		&cli.BoolFlag{
+			Name:  "exit-status",
+			Usage: "Exit with a code reflecting the outcome of the newest pipeline",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "How often to refresh the status when watching",
+			Value: 10 * time.Second,
+		},
+		&cli.StringFlag{
+			Name:  "commit",
+			Usage: "Show the pipelines of a commit instead of the branch, e.g. a SHA or HEAD",
+		},
+		&cli.StringFlag{
+			Name:  "tag",
+			Usage: "Show the pipelines of a tag instead of the branch",
+		},
+		&cli.StringFlag{
+			Name:  "template",
+			Usage: "Render the status with a Go template, see the README for the data and functions available",
+		},
+		&cli.StringFlag{
+			Name:  "template-file",
+			Usage: "Render the status with a Go template read from a file",
+		},
+	},
+	Action: action,
 }
 
 func action(c *cli.Context) error {
@@ -27,11 +73,120 @@ func action(c *cli.Context) error {
 		return cli.NewExitError(err.Error(), -1)
 	}
 
-	s, err := status.Check(ctx, client, c.String("branch"), c.Uint64("limit"))
+	format, err := global.Format(c)
 	if err != nil {
 		return cli.NewExitError(err.Error(), -1)
 	}
 
-	fmt.Println(s)
+	text, err := global.Template(c, "status", format)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	render := func(s *status.Status) (string, error) {
+		if text != "" {
+			return s.Template(text)
+		}
+		return s.Format(format)
+	}
+
+	f, err := filter(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	if c.Bool("watch") {
+		interval, err := global.Interval(c)
+		if err != nil {
+			return cli.NewExitError(err.Error(), -1)
+		}
+
+		// Redrawing in place only makes sense for text on a terminal,
+		// otherwise it would corrupt json, yaml or piped output.
+		redraw := (text != "" || format == "" || format == status.FormatText) &&
+			term.IsTerminal(int(os.Stdout.Fd()))
+		return watch(ctx, c, client, f, interval, redraw, render)
+	}
+
+	s, err := status.Check(ctx, client, f, c.Uint64("limit"))
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	out, err := render(s)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	fmt.Println(out)
+
+	if c.Bool("exit-status") {
+		return exit.Outcome(s.Outcome())
+	}
 	return nil
 }
+
+// filter returns the filter for the pipelines to show. With --commit or
+// --tag the branch is only used if it was set explicitly, since the commit
+// or tag may not be on the current branch.
+func filter(c *cli.Context) (*circleci.PipelineFilter, error) {
+	if !c.IsSet("commit") && !c.IsSet("tag") {
+		return global.Filter(c), nil
+	}
+
+	f := &circleci.PipelineFilter{Tag: c.String("tag")}
+	if c.IsSet("branch") {
+		f.Branch = c.String("branch")
+	}
+
+	if c.IsSet("commit") {
+		sha, err := global.Commit(c.String("commit"))
+		if err != nil {
+			return nil, err
+		}
+		f.Revision = sha
+	}
+	return f, nil
+}
+
+// watch polls the status, redrawing it in place if redraw is set, until every
+// workflow of the newest pipeline is done. It exits with a code reflecting the
+// outcome of the pipeline, or CodeInterrupted if it is canceled.
+func watch(ctx context.Context, c *cli.Context, client *circleci.Client, f *circleci.PipelineFilter, interval time.Duration, redraw bool, render func(*status.Status) (string, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lines int
+	for {
+		s, err := status.Check(ctx, client, f, c.Uint64("limit"))
+		if errors.Is(err, context.Canceled) {
+			return exit.Interrupted()
+		}
+		if err != nil {
+			return exit.Error(err)
+		}
+
+		out, err := render(s)
+		if err != nil {
+			return cli.NewExitError(err.Error(), -1)
+		}
+
+		if redraw && lines > 0 {
+			// Move the cursor up to the start of the previous output
+			// and clear everything below it.
+			fmt.Printf("\033[%dA\033[J", lines)
+		}
+		fmt.Println(out)
+		lines = strings.Count(out, "\n") + 1
+
+		if s.Done() {
+			return exit.Outcome(s.Outcome())
+		}
+
+		select {
+		case <-ctx.Done():
+			return exit.Interrupted()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package tests provides the tests subcommand.
+package tests
+
+import (
+	"fmt"
+
+	"github.com/tmessi/cci/internal/command/internal/complete"
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/resolve"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/tmessi/cci/internal/tests"
+	"github.com/urfave/cli/v2"
+)
+
+// Command is the tests subcommand.
+var Command = &cli.Command{
+	Name:         "tests",
+	ArgsUsage:    resolve.JobUsage,
+	Aliases:      []string{"test", "t"},
+	Usage:        "Show the test results of a job",
+	BashComplete: complete.Job,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "The output format, one of: text, junit",
+			Value:   tests.FormatText,
+		},
+	},
+	Action: action,
+}
+
+func action(c *cli.Context) error {
+	ctx, cancel := signal.InitContext()
+	defer cancel()
+
+	client, err := global.Client(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	n, err := resolve.Job(ctx, c, client)
+	if err != nil {
+		return err
+	}
+
+	s, err := tests.Summarize(ctx, client, n)
+	if err != nil {
+		return exit.Error(err)
+	}
+
+	out, err := s.Format(c.String("format"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	fmt.Println(out)
+	return nil
+}
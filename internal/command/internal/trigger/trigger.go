@@ -0,0 +1,91 @@
+// Package trigger provides the trigger subcommand.
+package trigger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tmessi/cci/internal/command/internal/exit"
+	"github.com/tmessi/cci/internal/command/internal/global"
+	"github.com/tmessi/cci/internal/command/internal/signal"
+	"github.com/tmessi/cci/internal/trigger"
+	"github.com/urfave/cli/v2"
+)
+
+// Command is the trigger subcommand.
+var Command = &cli.Command{
+	Name:    "trigger",
+	Aliases: []string{"tr"},
+	Usage:   "Trigger a new pipeline for a branch or tag",
+	Flags: []cli.Flag{
+		global.BranchFlag("Trigger the pipeline for this branch, defaults to the current branch"),
+		&cli.StringFlag{
+			Name:  "tag",
+			Usage: "Trigger the pipeline for this tag instead of a branch",
+		},
+		&cli.StringSliceFlag{
+			Name:    "param",
+			Aliases: []string{"p"},
+			Usage:   "A pipeline parameter of the form `key=value`, can be repeated",
+		},
+		&cli.BoolFlag{
+			Name:    "wait",
+			Aliases: []string{"w"},
+			Usage:   "Wait for the pipeline to finish and report its status",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "How often to check the pipeline with --wait",
+			Value: 10 * time.Second,
+		},
+	},
+	Action: action,
+}
+
+func action(c *cli.Context) error {
+	ctx, cancel := signal.InitContext()
+	defer cancel()
+
+	client, err := global.Client(c)
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	params, err := trigger.ParseParameters(c.StringSlice("param"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), -1)
+	}
+
+	// Check the interval before triggering, rather than after.
+	var interval time.Duration
+	if c.Bool("wait") {
+		interval, err = global.Interval(c)
+		if err != nil {
+			return cli.NewExitError(err.Error(), -1)
+		}
+	}
+
+	branch, branchSet := global.Branch(c)
+	tag := c.String("tag")
+	if tag != "" && !branchSet {
+		// The branch defaults to the current branch,
+		// only use it for a tag if it was asked for.
+		branch = ""
+	}
+
+	p, err := trigger.Pipeline(ctx, client, branch, tag, params)
+	if err != nil {
+		return exit.Error(err)
+	}
+	fmt.Printf("%d pipeline: %s\n", p.Number, p.State)
+
+	if c.Bool("wait") {
+		s, err := trigger.Wait(ctx, client, p, interval)
+		if err != nil {
+			return exit.Error(err)
+		}
+		fmt.Println(s)
+		return exit.Outcome(s.Outcome())
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package credential is used to store CircleCI tokens outside of
+// flags and environment variables. Tokens are stored per CircleCI url,
+// using the OS keyring, an encrypted file, or a credential helper.
+package credential
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Known errors.
+var (
+	ErrNotFound     = errors.New("credential not found")
+	ErrUnknownStore = errors.New("unknown credential store")
+	ErrNoHelper     = errors.New("no credential helper configured")
+)
+
+// Kinds of Store.
+const (
+	KindAuto    = "auto"
+	KindKeyring = "keyring"
+	KindFile    = "file"
+	KindHelper  = "helper"
+)
+
+// Store stores tokens keyed by the root url of the CircleCI api.
+type Store interface {
+	// Get returns the token for url, or ErrNotFound.
+	Get(url string) (string, error)
+	// Set stores the token for url.
+	Set(url, token string) error
+	// Delete removes the token for url. It is not an error
+	// if there is no token for url.
+	Delete(url string) error
+	// String describes the Store.
+	String() string
+}
+
+// Options are used to create a Store.
+type Options struct {
+	// Kind is the kind of Store to use. If empty or KindAuto, a helper
+	// is used if configured, otherwise the keyring if available,
+	// otherwise a file.
+	Kind string
+	// Helper is the command of the credential helper.
+	Helper string
+	// File is the path of the encrypted file.
+	File string
+	// Passphrase returns the passphrase used to encrypt the file.
+	Passphrase func() (string, error)
+}
+
+// New creates the Store described by the options.
+func New(o *Options) (Store, error) {
+	switch o.Kind {
+	case "", KindAuto:
+		if o.Helper != "" {
+			return &Helper{Command: o.Helper}, nil
+		}
+		if keyringAvailable() {
+			return &Keyring{}, nil
+		}
+		return &File{Path: o.File, Passphrase: o.Passphrase}, nil
+	case KindKeyring:
+		return &Keyring{}, nil
+	case KindFile:
+		return &File{Path: o.File, Passphrase: o.Passphrase}, nil
+	case KindHelper:
+		if o.Helper == "" {
+			return nil, ErrNoHelper
+		}
+		return &Helper{Command: o.Helper}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStore, o.Kind)
+	}
+}
@@ -0,0 +1,189 @@
+package credential_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmessi/cci/internal/credential"
+	"github.com/zalando/go-keyring"
+)
+
+const testURL = "https://circleci.com"
+
+func passphrase(p string) func() (string, error) {
+	return func() (string, error) { return p, nil }
+}
+
+// helperScript is a credential helper that stores tokens in a directory,
+// one file per url.
+func helperScript(dir string) string {
+	return fmt.Sprintf(`f() {
+	while IFS='=' read -r k v && [ -n "$k" ]; do
+		case "$k" in
+		url) url="$v" ;;
+		token) token="$v" ;;
+		esac
+	done
+	file="%s/$(echo "$url" | tr -c 'a-z0-9\n' _)"
+	case "$1" in
+	get) if [ -f "$file" ]; then echo "token=$(cat "$file")"; fi ;;
+	store) echo "$token" > "$file" ;;
+	erase) rm -f "$file" ;;
+	esac
+}; f`, dir)
+}
+
+func TestStore(t *testing.T) {
+	keyring.MockInit()
+
+	tests := []struct {
+		name  string
+		store func(t *testing.T) credential.Store
+	}{
+		{
+			"Keyring",
+			func(t *testing.T) credential.Store {
+				return &credential.Keyring{}
+			},
+		},
+		{
+			"File",
+			func(t *testing.T) credential.Store {
+				return &credential.File{
+					Path:       filepath.Join(t.TempDir(), "credentials"),
+					Passphrase: passphrase("secret"),
+				}
+			},
+		},
+		{
+			"Helper",
+			func(t *testing.T) credential.Store {
+				return &credential.Helper{Command: helperScript(t.TempDir())}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.store(t)
+
+			if _, err := s.Get(testURL); !errors.Is(err, credential.ErrNotFound) {
+				t.Fatalf("Get before Set: got %v, wanted %v", err, credential.ErrNotFound)
+			}
+
+			if err := s.Set(testURL, "valid-token"); err != nil {
+				t.Fatalf("Set: %s", err.Error())
+			}
+
+			token, err := s.Get(testURL)
+			if err != nil {
+				t.Fatalf("Get: %s", err.Error())
+			}
+			if token != "valid-token" {
+				t.Errorf("Get: got %q, wanted %q", token, "valid-token")
+			}
+
+			if _, err := s.Get("https://circleci.example.com"); !errors.Is(err, credential.ErrNotFound) {
+				t.Errorf("Get other url: got %v, wanted %v", err, credential.ErrNotFound)
+			}
+
+			if err := s.Delete(testURL); err != nil {
+				t.Fatalf("Delete: %s", err.Error())
+			}
+
+			if _, err := s.Get(testURL); !errors.Is(err, credential.ErrNotFound) {
+				t.Errorf("Get after Delete: got %v, wanted %v", err, credential.ErrNotFound)
+			}
+
+			if err := s.Delete(testURL); err != nil {
+				t.Errorf("Delete twice: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestFileWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+
+	s := &credential.File{Path: path, Passphrase: passphrase("secret")}
+	if err := s.Set(testURL, "valid-token"); err != nil {
+		t.Fatalf("Set: %s", err.Error())
+	}
+
+	s = &credential.File{Path: path, Passphrase: passphrase("wrong")}
+	if _, err := s.Get(testURL); err == nil {
+		t.Errorf("expected error decrypting with the wrong passphrase")
+	}
+}
+
+func TestNew(t *testing.T) {
+	keyring.MockInit()
+
+	tests := []struct {
+		name     string
+		opts     *credential.Options
+		expected credential.Store
+		err      error
+	}{
+		{
+			"AutoHelper",
+			&credential.Options{Helper: "pass-cci"},
+			&credential.Helper{Command: "pass-cci"},
+			nil,
+		},
+		{
+			"AutoKeyring",
+			&credential.Options{Kind: credential.KindAuto},
+			&credential.Keyring{},
+			nil,
+		},
+		{
+			"File",
+			&credential.Options{Kind: credential.KindFile, File: "/tmp/credentials"},
+			&credential.File{Path: "/tmp/credentials"},
+			nil,
+		},
+		{
+			"HelperNotConfigured",
+			&credential.Options{Kind: credential.KindHelper},
+			nil,
+			credential.ErrNoHelper,
+		},
+		{
+			"Unknown",
+			&credential.Options{Kind: "vault"},
+			nil,
+			credential.ErrUnknownStore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := credential.New(tt.opts)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err: got %v, wanted %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			if s.String() != tt.expected.String() {
+				t.Errorf("got %s, wanted %s", s, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelperFails(t *testing.T) {
+	s := &credential.Helper{Command: "exit 1;"}
+
+	if _, err := s.Get(testURL); !errors.Is(err, credential.ErrNotFound) {
+		t.Errorf("Get: got %v, wanted %v", err, credential.ErrNotFound)
+	}
+
+	if err := s.Set(testURL, "valid-token"); err == nil {
+		t.Errorf("Set: expected error, but did not get one")
+	}
+}
@@ -0,0 +1,148 @@
+package credential
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// File stores tokens in a file, encrypted with a key derived from a
+// passphrase. It is a fallback for systems without a keyring.
+type File struct {
+	Path       string
+	Passphrase func() (string, error)
+}
+
+// encrypted is a single token encrypted with AES-GCM. The key is derived
+// from the passphrase and salt with scrypt.
+type encrypted struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (f *File) read() (map[string]*encrypted, error) {
+	tokens := make(map[string]*encrypted)
+
+	b, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &tokens); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Path, err)
+	}
+	return tokens, nil
+}
+
+func (f *File) write(tokens map[string]*encrypted) error {
+	b, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, b, 0o600)
+}
+
+func (f *File) gcm(salt []byte) (cipher.AEAD, error) {
+	passphrase, err := f.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Get decrypts and returns the token for url.
+func (f *File) Get(url string) (string, error) {
+	tokens, err := f.read()
+	if err != nil {
+		return "", err
+	}
+
+	e, ok := tokens[url]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	gcm, err := f.gcm(e.Salt)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := gcm.Open(nil, e.Nonce, e.Ciphertext, []byte(url))
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt token, is the passphrase correct? %w", err)
+	}
+	return string(token), nil
+}
+
+// Set encrypts and stores the token for url.
+func (f *File) Set(url, token string) error {
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	gcm, err := f.gcm(salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	tokens[url] = &encrypted{
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, []byte(token), []byte(url)),
+	}
+	return f.write(tokens)
+}
+
+// Delete removes the token for url.
+func (f *File) Delete(url string) error {
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tokens[url]; !ok {
+		return nil
+	}
+	delete(tokens, url)
+	return f.write(tokens)
+}
+
+func (f *File) String() string {
+	return fmt.Sprintf("file %s", f.Path)
+}
@@ -0,0 +1,83 @@
+package credential
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Helper stores tokens using an external command, like a git credential
+// helper. The command is run by the shell with one of the actions get,
+// store or erase appended. Attributes are passed on stdin as key=value
+// lines, terminated by a blank line:
+//
+//	url=https://circleci.com
+//	token=<token>
+//
+// The token is only passed for store. For get, the helper should
+// output the token attribute, or nothing if it has no token for the url.
+type Helper struct {
+	Command string
+}
+
+func (h *Helper) run(action string, attrs map[string]string) ([]byte, error) {
+	var in bytes.Buffer
+	for _, k := range []string{"url", "token"} {
+		if v, ok := attrs[k]; ok {
+			fmt.Fprintf(&in, "%s=%s\n", k, v)
+		}
+	}
+	in.WriteString("\n")
+
+	cmd := exec.Command("sh", "-c", h.Command+" "+action)
+	cmd.Stdin = &in
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %s: %w", action, err)
+	}
+	return out, nil
+}
+
+// Get returns the token for url from the helper. Like git, a helper that
+// exits with a non-zero status is treated as having no token.
+func (h *Helper) Get(url string) (string, error) {
+	out, err := h.run("get", map[string]string{"url": url})
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), "=")
+		if ok && k == "token" && v != "" {
+			return v, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// Set stores the token for url with the helper.
+func (h *Helper) Set(url, token string) error {
+	_, err := h.run("store", map[string]string{"url": url, "token": token})
+	return err
+}
+
+// Delete removes the token for url with the helper.
+func (h *Helper) Delete(url string) error {
+	_, err := h.run("erase", map[string]string{"url": url})
+	return err
+}
+
+func (h *Helper) String() string {
+	return fmt.Sprintf("credential-helper %q", h.Command)
+}
@@ -0,0 +1,48 @@
+package credential
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the name tokens are stored under in the keyring.
+const keyringService = "cci"
+
+// Keyring stores tokens in the OS keyring. On Linux this is the
+// Secret Service, on macOS the Keychain, and on Windows the
+// Credential Manager.
+type Keyring struct{}
+
+// keyringAvailable reports whether the OS keyring can be used.
+func keyringAvailable() bool {
+	_, err := keyring.Get(keyringService, "")
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// Get returns the token for url from the keyring.
+func (k *Keyring) Get(url string) (string, error) {
+	token, err := keyring.Get(keyringService, url)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return token, err
+}
+
+// Set stores the token for url in the keyring.
+func (k *Keyring) Set(url, token string) error {
+	return keyring.Set(keyringService, url, token)
+}
+
+// Delete removes the token for url from the keyring.
+func (k *Keyring) Delete(url string) error {
+	err := keyring.Delete(keyringService, url)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (k *Keyring) String() string {
+	return "keyring"
+}
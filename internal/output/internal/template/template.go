@@ -8,8 +8,11 @@ import (
 )
 
 const output = `
+{{- define "header" }}-- {{ .Name | printf "%-50s" }} [{{ .Index }}] {{ .Status }}
+{{- with .ExitCode }} (exit {{ . }}){{ end }}
+{{- if .RunTime }} {{ .RunTime }}{{ end }} -------------------------- {{ end }}
 {{- range .Steps }}
--- {{ .Name | printf "%-50s" }} -------------------------- 
+{{ template "header" . }}
 
 {{ .Output }}
 {{ end -}}
@@ -30,3 +33,13 @@ func Render(data interface{}) string {
 	}
 	return b.String()
 }
+
+// RenderHeader will render the header of a single step.
+func RenderHeader(step interface{}) string {
+	var b bytes.Buffer
+	err := tmpl.ExecuteTemplate(&b, "header", step)
+	if err != nil {
+		panic(err)
+	}
+	return b.String()
+}
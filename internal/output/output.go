@@ -4,16 +4,35 @@ package output
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/tmessi/cci/internal/circleci"
 	"github.com/tmessi/cci/internal/output/internal/template"
+	"github.com/tmessi/cci/internal/render"
+	"golang.org/x/sync/errgroup"
 )
 
 // Step is a stage in a build. It has a name and the output
-// of the step.
+// of the step. A step of a job with parallelism is run on each
+// container, the Index identifies the container.
 type Step struct {
-	Name   string
-	Output string
+	Name     string
+	Index    uint64
+	Status   string
+	ExitCode *int
+	RunTime  time.Duration
+	Output   string
+}
+
+func newStep(step *circleci.BuildStep, action *circleci.BuildAction) *Step {
+	return &Step{
+		Name:     step.Name,
+		Index:    action.Index,
+		Status:   action.Status,
+		ExitCode: action.ExitCode,
+		RunTime:  time.Duration(action.RunTimeMillis) * time.Millisecond,
+	}
 }
 
 func (s *Step) String() string {
@@ -29,32 +48,155 @@ func (b *Build) String() string {
 	return template.Render(b)
 }
 
+// Template renders the Build with a user supplied text/template.
+// The template has the functions provided by the render package.
+func (b *Build) Template(text string) (string, error) {
+	return render.Template(text, b)
+}
+
+// Options control which steps of a build are retrieved and how.
+type Options struct {
+	// Container, if set, only includes steps run on the parallel
+	// container with this index.
+	Container *uint64
+	// FailedOnly only includes steps that failed.
+	FailedOnly bool
+	// Concurrency limits the number of outputs that are retrieved
+	// at the same time. A value less than one is treated as one,
+	// like circleci.WithConcurrency.
+	Concurrency int
+}
+
+func (o *Options) include(action *circleci.BuildAction) bool {
+	if o == nil {
+		return true
+	}
+	if o.Container != nil && *o.Container != action.Index {
+		return false
+	}
+	if o.FailedOnly && !action.HasFailed() {
+		return false
+	}
+	return true
+}
+
 type client interface {
 	Build(context.Context, uint64) (*circleci.BuildResponse, error)
 	BuildActionOutput(context.Context, *circleci.BuildAction) (string, error)
+	LiveActionOutput(context.Context, uint64, *circleci.BuildAction) (string, error)
 }
 
-// GetBuild retrieves the output of given build number.
-func GetBuild(ctx context.Context, c client, buildNum uint64) (*Build, error) {
+// GetBuild retrieves the output of given build number. The output of each
+// step is retrieved concurrently, but the steps are returned in their original order.
+func GetBuild(ctx context.Context, c client, buildNum uint64, opts *Options) (*Build, error) {
 	br, err := c.Build(ctx, buildNum)
 	if err != nil {
 		return nil, err
 	}
 
 	steps := make([]*Step, 0, len(br.Steps))
+	actions := make([]*circleci.BuildAction, 0, len(br.Steps))
 	for _, step := range br.Steps {
 		for _, action := range step.Actions {
-			if action.HasOutput {
-				o, err := c.BuildActionOutput(ctx, action)
+			if action.HasOutput && opts.include(action) {
+				steps = append(steps, newStep(step, action))
+				actions = append(actions, action)
+			}
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	limit := 1
+	if opts != nil && opts.Concurrency > 1 {
+		limit = opts.Concurrency
+	}
+	g.SetLimit(limit)
+
+	for i := range steps {
+		i := i // https://golang.org/doc/faq#closures_and_goroutines
+
+		g.Go(func() error {
+			o, err := c.BuildActionOutput(gctx, actions[i])
+			if err != nil {
+				return err
+			}
+			steps[i].Output = o
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return &Build{Steps: steps}, nil
+}
+
+type actionKey struct {
+	step, index uint64
+}
+
+// Follow writes the output of the given build number to w as it is produced.
+// It polls the build every interval, writing new steps and any new output of
+// running steps, until the build has finished or the context is done.
+func Follow(ctx context.Context, c client, buildNum uint64, opts *Options, interval time.Duration, w io.Writer) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// written tracks how much of the output of each action has been written.
+	written := make(map[actionKey]int)
+	finished := make(map[actionKey]bool)
+
+	for {
+		br, err := c.Build(ctx, buildNum)
+		if err != nil {
+			return err
+		}
+
+		for _, step := range br.Steps {
+			for _, action := range step.Actions {
+				key := actionKey{action.Step, action.Index}
+				if finished[key] {
+					continue
+				}
+				if !opts.include(action) || (!action.Running() && !action.HasOutput) {
+					// A running action may be included once it finishes,
+					// for example if it fails, so check it again later.
+					finished[key] = !action.Running()
+					continue
+				}
+
+				var o string
+				switch {
+				case action.Running():
+					o, err = c.LiveActionOutput(ctx, buildNum, action)
+				case action.HasOutput:
+					o, err = c.BuildActionOutput(ctx, action)
+				}
 				if err != nil {
-					return nil, err
+					return err
+				}
+
+				n, started := written[key]
+				if !started {
+					fmt.Fprintf(w, "\n%s\n\n", template.RenderHeader(newStep(step, action)))
+				}
+				if len(o) > n {
+					fmt.Fprint(w, o[n:])
 				}
-				steps = append(steps, &Step{
-					Name:   step.Name,
-					Output: o,
-				})
+				written[key] = len(o)
+				finished[key] = !action.Running()
 			}
 		}
+
+		if br.Finished() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
-	return &Build{Steps: steps}, nil
 }
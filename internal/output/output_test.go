@@ -0,0 +1,241 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/output"
+)
+
+// testClient returns the next build in builds on each call to Build.
+// Output is looked up by the step name and the number of calls to Build.
+type testClient struct {
+	builds  []*circleci.BuildResponse
+	outputs map[uint64][]string
+	calls   int
+}
+
+func (c *testClient) Build(_ context.Context, _ uint64) (*circleci.BuildResponse, error) {
+	b := c.builds[c.calls]
+	c.calls++
+	return b, nil
+}
+
+func (c *testClient) output(a *circleci.BuildAction) string {
+	return c.outputs[a.Step][c.calls-1]
+}
+
+func (c *testClient) BuildActionOutput(_ context.Context, a *circleci.BuildAction) (string, error) {
+	return c.output(a), nil
+}
+
+func (c *testClient) LiveActionOutput(_ context.Context, _ uint64, a *circleci.BuildAction) (string, error) {
+	return c.output(a), nil
+}
+
+func TestFollow(t *testing.T) {
+	step := func(name string, num uint64, status string) *circleci.BuildStep {
+		return &circleci.BuildStep{
+			Name: name,
+			Actions: []*circleci.BuildAction{
+				{Step: num, Status: status, HasOutput: status != "running"},
+			},
+		}
+	}
+
+	client := &testClient{
+		builds: []*circleci.BuildResponse{
+			{
+				Lifecycle: "running",
+				Steps: []*circleci.BuildStep{
+					step("checkout", 0, "success"),
+					step("test", 1, "running"),
+				},
+			},
+			{
+				Lifecycle: "running",
+				Steps: []*circleci.BuildStep{
+					step("checkout", 0, "success"),
+					step("test", 1, "running"),
+				},
+			},
+			{
+				Lifecycle: "finished",
+				Steps: []*circleci.BuildStep{
+					step("checkout", 0, "success"),
+					step("test", 1, "failed"),
+					step("upload", 2, "success"),
+				},
+			},
+		},
+		outputs: map[uint64][]string{
+			0: {"cloned\n", "cloned\n", "cloned\n"},
+			1: {"=== RUN a\n", "=== RUN a\n--- PASS: a\n", "=== RUN a\n--- PASS: a\n--- FAIL: b\n"},
+			2: {"", "", "uploaded\n"},
+		},
+	}
+
+	var b bytes.Buffer
+	err := output.Follow(context.Background(), client, 1, nil, time.Millisecond, &b)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if client.calls != 3 {
+		t.Errorf("calls: got %d, wanted %d", client.calls, 3)
+	}
+
+	got := b.String()
+	for _, want := range []string{"cloned\n", "=== RUN a\n--- PASS: a\n--- FAIL: b\n", "uploaded\n"} {
+		if strings.Count(got, want) != 1 {
+			t.Errorf("expected output to contain %q once, got:\n%s", want, got)
+		}
+	}
+	for _, header := range []string{"-- checkout ", "-- test ", "-- upload "} {
+		if strings.Count(got, header) != 1 {
+			t.Errorf("expected output to contain header %q once, got:\n%s", header, got)
+		}
+	}
+}
+
+func TestGetBuild(t *testing.T) {
+	exitCode := 1
+	container := uint64(1)
+
+	newClient := func() *testClient {
+		return &testClient{
+			builds: []*circleci.BuildResponse{
+				{
+					Lifecycle: "finished",
+					Steps: []*circleci.BuildStep{
+						{
+							Name: "checkout",
+							Actions: []*circleci.BuildAction{
+								{Step: 0, Index: 0, Status: "success", HasOutput: true},
+								{Step: 0, Index: 1, Status: "success", HasOutput: true},
+							},
+						},
+						{
+							Name: "test",
+							Actions: []*circleci.BuildAction{
+								{Step: 1, Index: 0, Status: "success", HasOutput: true, RunTimeMillis: 1500},
+								{Step: 1, Index: 1, Status: "failed", HasOutput: true, ExitCode: &exitCode},
+							},
+						},
+						{
+							Name: "noop",
+							Actions: []*circleci.BuildAction{
+								{Step: 2, Index: 0, Status: "success", HasOutput: false},
+							},
+						},
+					},
+				},
+			},
+			outputs: map[uint64][]string{
+				0: {"cloned\n"},
+				1: {"ran tests\n"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     *output.Options
+		expected []*output.Step
+		label    string
+	}{
+		{
+			"AllContainers",
+			&output.Options{Concurrency: 2},
+			[]*output.Step{
+				{Name: "checkout", Index: 0, Status: "success", Output: "cloned\n"},
+				{Name: "checkout", Index: 1, Status: "success", Output: "cloned\n"},
+				{Name: "test", Index: 0, Status: "success", RunTime: 1500 * time.Millisecond, Output: "ran tests\n"},
+				{Name: "test", Index: 1, Status: "failed", ExitCode: &exitCode, Output: "ran tests\n"},
+			},
+			"[0] success 1.5s",
+		},
+		{
+			"SingleContainer",
+			&output.Options{Container: &container},
+			[]*output.Step{
+				{Name: "checkout", Index: 1, Status: "success", Output: "cloned\n"},
+				{Name: "test", Index: 1, Status: "failed", ExitCode: &exitCode, Output: "ran tests\n"},
+			},
+			"[1] failed (exit 1)",
+		},
+		{
+			"FailedOnly",
+			&output.Options{FailedOnly: true},
+			[]*output.Step{
+				{Name: "test", Index: 1, Status: "failed", ExitCode: &exitCode, Output: "ran tests\n"},
+			},
+			"[1] failed (exit 1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := output.GetBuild(context.Background(), newClient(), 1, tt.opts)
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if len(b.Steps) != len(tt.expected) {
+				t.Fatalf("Steps: got %d, wanted %d", len(b.Steps), len(tt.expected))
+			}
+
+			for i, got := range b.Steps {
+				want := tt.expected[i]
+
+				if got.Name != want.Name {
+					t.Errorf("Name: got %q, want %q", got.Name, want.Name)
+				}
+				if got.Index != want.Index {
+					t.Errorf("Index: got %d, want %d", got.Index, want.Index)
+				}
+				if got.Status != want.Status {
+					t.Errorf("Status: got %q, want %q", got.Status, want.Status)
+				}
+				if (got.ExitCode == nil) != (want.ExitCode == nil) {
+					t.Errorf("ExitCode: got %v, want %v", got.ExitCode, want.ExitCode)
+				}
+				if got.RunTime != want.RunTime {
+					t.Errorf("RunTime: got %s, want %s", got.RunTime, want.RunTime)
+				}
+				if got.Output != want.Output {
+					t.Errorf("Output: got %q, want %q", got.Output, want.Output)
+				}
+			}
+
+			if rendered := b.String(); !strings.Contains(rendered, tt.label) {
+				t.Errorf("expected rendered output to contain %q, got:\n%s", tt.label, rendered)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	exitCode := 1
+	b := &output.Build{
+		Steps: []*output.Step{
+			{Name: "checkout", Index: 0, Status: "success", RunTime: 1500 * time.Millisecond, Output: "cloned\n"},
+			{Name: "test", Index: 1, Status: "failed", ExitCode: &exitCode, RunTime: 65 * time.Second, Output: "--- FAIL: b\n"},
+		},
+	}
+
+	text := `{{ range .Steps }}{{ .Name | pad 10 }}[{{ .Index }}] {{ .Status }} {{ with .ExitCode }}{{ . }} {{ end }}{{ .RunTime | elapsed }}: {{ .Output }}{{ end }}`
+	out, err := b.Template(text)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	expected := "checkout  [0] success 1s: cloned\ntest      [1] failed 1 1m 5s: --- FAIL: b\n"
+	if out != expected {
+		t.Errorf("got %q, wanted %q", out, expected)
+	}
+}
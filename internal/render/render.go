@@ -0,0 +1,194 @@
+// Package render provides the functions available in templates, and
+// renders templates supplied by users. The built in template for the text
+// output of status shares the same functions.
+//
+// User supplied templates use text/template, with these functions:
+//
+//	since     the time.Duration since a *time.Time
+//	between   the time.Duration between a start and stop *time.Time,
+//	          or until now if the stop is nil
+//	duration  the largest unit of a time.Duration, e.g. 3 hours
+//	elapsed   the two largest units of a time.Duration, e.g. 1h 5m
+//	short     an abbreviated commit SHA
+//	color     wraps a string in an ANSI color, e.g. color "red"
+//	truncate  truncates a string to a number of characters
+//	pad       pads a string with spaces to a number of characters,
+//	          a negative number pads on the left
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+func since(t *time.Time) time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Since(*t)
+}
+
+// between returns the time from start until stop, or until now
+// if stop is nil because it has not stopped yet.
+func between(start, stop *time.Time) time.Duration {
+	if start == nil {
+		return 0
+	}
+	if stop == nil {
+		return time.Since(*start)
+	}
+	return stop.Sub(*start)
+}
+
+// unit is the number of a single unit of time in a duration.
+type unit struct {
+	n      int64
+	name   string
+	suffix string
+}
+
+// units splits a duration into days, hours, minutes and seconds,
+// largest first, omitting any that are zero.
+func units(duration time.Duration) []unit {
+	all := []unit{
+		{int64(duration.Hours() / 24), "day", "d"},
+		{int64(math.Mod(duration.Hours(), 24)), "hour", "h"},
+		{int64(math.Mod(duration.Minutes(), 60)), "minute", "m"},
+		{int64(math.Mod(duration.Seconds(), 60)), "second", "s"},
+	}
+
+	u := make([]unit, 0, len(all))
+	for _, a := range all {
+		if a.n > 0 {
+			u = append(u, a)
+		}
+	}
+	return u
+}
+
+// duration prints the largest unit of the duration, e.g. 3 hours.
+func duration(duration time.Duration) string {
+	u := units(duration)
+	if len(u) == 0 {
+		return ""
+	}
+
+	if u[0].n > 1 {
+		return fmt.Sprintf("%d %ss", u[0].n, u[0].name)
+	}
+	return fmt.Sprintf("%d %s", u[0].n, u[0].name)
+}
+
+// elapsed prints the two largest units of the duration in short form,
+// e.g. 1h 5m or 42s.
+func elapsed(duration time.Duration) string {
+	u := units(duration)
+	if len(u) == 0 {
+		return "0s"
+	}
+	if len(u) > 2 {
+		u = u[:2]
+	}
+
+	parts := make([]string, 0, len(u))
+	for _, a := range u {
+		parts = append(parts, fmt.Sprintf("%d%s", a.n, a.suffix))
+	}
+	return strings.Join(parts, " ")
+}
+
+// short abbreviates a commit SHA.
+func short(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+var colors = map[string]string{
+	"bold":    "1",
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"gray":    "90",
+}
+
+// color wraps s in the ANSI escape codes for the named color.
+func color(name, s string) (string, error) {
+	code, ok := colors[name]
+	if !ok {
+		return "", fmt.Errorf("unknown color: %s", name)
+	}
+	return fmt.Sprintf("\033[%sm%s\033[0m", code, s), nil
+}
+
+// truncate shortens s to at most n characters, ending it with an
+// ellipsis if it was shortened.
+func truncate(n int, s string) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
+
+// pad pads s with spaces on the right to n characters,
+// or on the left if n is negative.
+func pad(n int, s string) string {
+	left := n < 0
+	if left {
+		n = -n
+	}
+
+	count := utf8.RuneCountInString(s)
+	if count >= n {
+		return s
+	}
+
+	if left {
+		return strings.Repeat(" ", n-count) + s
+	}
+	return s + strings.Repeat(" ", n-count)
+}
+
+// Funcs returns the functions available in templates.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"since":    since,
+		"between":  between,
+		"duration": duration,
+		"elapsed":  elapsed,
+		"short":    short,
+		"color":    color,
+		"truncate": truncate,
+		"pad":      pad,
+	}
+}
+
+// Template parses the user supplied text as a template and renders data
+// with it.
+func Template(text string, data interface{}) (string, error) {
+	tmpl, err := template.New("user").Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,67 @@
+package render_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmessi/cci/internal/render"
+)
+
+func TestTemplate(t *testing.T) {
+	start := time.Date(2021, 10, 24, 20, 2, 11, 0, time.UTC)
+	stop := start.Add(time.Hour + 5*time.Minute + 42*time.Second)
+
+	data := struct {
+		Name    string
+		SHA     string
+		Started *time.Time
+		Stopped *time.Time
+		Running *time.Time
+	}{
+		Name:    "unit-tests",
+		SHA:     "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39",
+		Started: &start,
+		Stopped: &stop,
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		err      bool
+	}{
+		{"Duration", `{{ between .Started .Stopped | duration }}`, "1 hour", false},
+		{"Elapsed", `{{ between .Started .Stopped | elapsed }}`, "1h 5m", false},
+		{"ElapsedZero", `{{ between .Running .Stopped | elapsed }}`, "0s", false},
+		{"Short", `{{ .SHA | short }}`, "4f2a9c1", false},
+		{"Color", `{{ .Name | color "red" }}`, "\033[31munit-tests\033[0m", false},
+		{"UnknownColor", `{{ .Name | color "mauve" }}`, "", true},
+		{"Truncate", `{{ .Name | truncate 5 }}`, "unit…", false},
+		{"TruncateShort", `{{ .Name | truncate 20 }}`, "unit-tests", false},
+		{"Pad", `[{{ .Name | pad 12 }}]`, "[unit-tests  ]", false},
+		{"PadLeft", `[{{ .Name | pad -12 }}]`, "[  unit-tests]", false},
+		{"PadLong", `[{{ .Name | pad 4 }}]`, "[unit-tests]", false},
+		{"ParseError", `{{ .Name `, "", true},
+		{"ExecError", `{{ .Missing }}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := render.Template(tt.text, data)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got output %q", out)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if out != tt.expected {
+				t.Errorf("got %q, wanted %q", out, tt.expected)
+			}
+		})
+	}
+}
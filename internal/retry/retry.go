@@ -3,14 +3,247 @@ package retry
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/status"
+)
+
+// Known errors.
+var (
+	ErrFromFailedWithJobs = errors.New("cannot retry from failed and retry specific jobs")
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobFinished        = errors.New("job finished before ssh was available")
+	ErrNoNewWorkflow      = errors.New("could not find the retried workflow")
 )
 
 type client interface {
-	RetryWorkflow(context.Context, string) (string, error)
+	RetryWorkflow(context.Context, string, *circleci.RetryOptions) (*circleci.RetryResponse, error)
+	Workflow(context.Context, string) (*circleci.Workflow, error)
+	Workflows(context.Context, *circleci.Pipeline) ([]*circleci.Workflow, error)
+	Build(context.Context, uint64) (*circleci.BuildResponse, error)
+}
+
+// Options control which jobs of a workflow are retried.
+type Options struct {
+	// FromFailed only retries the failed jobs.
+	FromFailed bool
+	// Jobs are the names of the jobs to retry.
+	Jobs []string
+}
+
+// Workflow will retrun the given workflow. By default the full workflow is
+// rerun, opts can be used to only rerun some of the jobs.
+func Workflow(ctx context.Context, c client, w *circleci.Workflow, opts *Options) (*circleci.RetryResponse, error) {
+	ro, err := retryOptions(w, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.RetryWorkflow(ctx, w.ID, ro)
+}
+
+func retryOptions(w *circleci.Workflow, opts *Options) (*circleci.RetryOptions, error) {
+	ro := &circleci.RetryOptions{}
+	if opts == nil {
+		return ro, nil
+	}
+
+	if opts.FromFailed && len(opts.Jobs) > 0 {
+		return nil, ErrFromFailedWithJobs
+	}
+	ro.FromFailed = opts.FromFailed
+
+	for _, name := range opts.Jobs {
+		id := jobID(w, name)
+		if id == "" {
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+		}
+		ro.Jobs = append(ro.Jobs, id)
+	}
+	return ro, nil
+}
+
+func jobID(w *circleci.Workflow, name string) string {
+	for _, j := range w.Jobs {
+		if j.Name == name {
+			return j.ID
+		}
+	}
+	return ""
+}
+
+// SSH will rerun the given job of the workflow with SSH enabled. It then
+// polls the new job every interval until the SSH details are available, and
+// returns the command to connect to it. The new workflow is looked up in the
+// same pipeline p as w if CircleCI does not return its ID. If the new
+// workflow is not found within maxFindPolls, ErrNoNewWorkflow is returned.
+func SSH(ctx context.Context, c client, p *circleci.Pipeline, w *circleci.Workflow, jobName string, interval time.Duration) (string, error) {
+	id := jobID(w, jobName)
+	if id == "" {
+		return "", fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
+	}
+
+	rr, err := c.RetryWorkflow(ctx, w.ID, &circleci.RetryOptions{
+		Jobs:      []string{id},
+		EnableSSH: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	id = rr.WorkflowID
+	var found bool
+	var num uint64
+	for polls := 1; ; polls++ {
+		if num == 0 {
+			if id == "" {
+				id, err = newWorkflowID(ctx, c, p, w)
+				if err != nil {
+					return "", err
+				}
+			}
+
+			if id != "" {
+				num, err = jobNumber(ctx, c, id, jobName)
+				switch {
+				case err == nil:
+					found = true
+				case !found && errors.Is(err, circleci.ErrNotFound):
+					// The workflow may not have been created yet.
+				default:
+					return "", err
+				}
+			}
+
+			if !found && polls >= maxFindPolls {
+				return "", fmt.Errorf("%w: %s after %d attempts", ErrNoNewWorkflow, w.Name, polls)
+			}
+		}
+
+		if num != 0 {
+			br, err := c.Build(ctx, num)
+			if err != nil {
+				return "", err
+			}
+
+			for _, n := range br.Nodes {
+				if n.SSHEnabled && n.PublicIPAddr != "" && n.Port != 0 {
+					return fmt.Sprintf("ssh -p %d %s", n.Port, n.PublicIPAddr), nil
+				}
+			}
+
+			if br.Finished() {
+				return "", ErrJobFinished
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
 }
 
-// Workflow will retrun the given workflow.
-func Workflow(ctx context.Context, c client, workflowID string) (string, error) {
-	s, err := c.RetryWorkflow(ctx, workflowID)
-	return s, err
+// jobNumber finds the number of the job in the workflow. The number
+// is zero until the job has started.
+func jobNumber(ctx context.Context, c client, workflowID, jobName string) (uint64, error) {
+	w, err := c.Workflow(ctx, workflowID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, j := range w.Jobs {
+		if j.Name == jobName {
+			return j.Number, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
+}
+
+// maxFindPolls is the number of polls after which Wait and SSH stop looking
+// for a retried workflow that has not shown up.
+const maxFindPolls = 30
+
+// Wait polls the workflow created by retrying w every interval until it is
+// done. The new workflow is looked up in the same pipeline p as w. It returns
+// the status of the pipeline with just the new workflow. If the new workflow
+// is not found within maxFindPolls, ErrNoNewWorkflow is returned.
+func Wait(ctx context.Context, c client, p *circleci.Pipeline, w *circleci.Workflow, rr *circleci.RetryResponse, interval time.Duration) (*status.Status, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	id := rr.WorkflowID
+	for polls := 1; ; polls++ {
+		var err error
+		if id == "" {
+			id, err = newWorkflowID(ctx, c, p, w)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var nw *circleci.Workflow
+		if id != "" {
+			nw, err = c.Workflow(ctx, id)
+			if err != nil && !errors.Is(err, circleci.ErrNotFound) {
+				return nil, err
+			}
+		}
+
+		if nw == nil && polls >= maxFindPolls {
+			return nil, fmt.Errorf("%w: %s after %d attempts", ErrNoNewWorkflow, w.Name, polls)
+		}
+
+		if nw != nil {
+			s := &status.Status{
+				Pipelines: []*circleci.Pipeline{
+					{
+						ID:        p.ID,
+						Number:    p.Number,
+						State:     p.State,
+						Updated:   p.Updated,
+						Workflows: []*circleci.Workflow{nw},
+					},
+				},
+			}
+			if s.Done() {
+				return s, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// newWorkflowID finds the ID of the workflow in p with the same name as w
+// that was not already part of p. It returns an empty ID if the workflow
+// has not been created yet.
+func newWorkflowID(ctx context.Context, c client, p *circleci.Pipeline, w *circleci.Workflow) (string, error) {
+	workflows, err := c.Workflows(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	known := make(map[string]bool, len(p.Workflows))
+	for _, pw := range p.Workflows {
+		known[pw.ID] = true
+	}
+
+	for _, nw := range workflows {
+		if nw.Name == w.Name && !known[nw.ID] {
+			return nw.ID, nil
+		}
+	}
+	return "", nil
 }
@@ -0,0 +1,386 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/retry"
+	"github.com/tmessi/cci/internal/status"
+)
+
+type testClient struct {
+	workflowID string
+	opts       *circleci.RetryOptions
+
+	// workflows, pipelineWorkflows and builds are returned in order on each call.
+	// The last of pipelineWorkflows is returned once the others are used. A nil
+	// workflow is not found.
+	workflows         []*circleci.Workflow
+	pipelineWorkflows [][]*circleci.Workflow
+	builds            []*circleci.BuildResponse
+	rr                *circleci.RetryResponse
+
+	// workflowErr is returned by Workflow if set.
+	workflowErr error
+}
+
+func (c *testClient) RetryWorkflow(_ context.Context, workflowID string, opts *circleci.RetryOptions) (*circleci.RetryResponse, error) {
+	c.workflowID = workflowID
+	c.opts = opts
+	if c.rr != nil {
+		return c.rr, nil
+	}
+	return &circleci.RetryResponse{WorkflowID: "w2"}, nil
+}
+
+func (c *testClient) Workflows(_ context.Context, _ *circleci.Pipeline) ([]*circleci.Workflow, error) {
+	w := c.pipelineWorkflows[0]
+	if len(c.pipelineWorkflows) > 1 {
+		c.pipelineWorkflows = c.pipelineWorkflows[1:]
+	}
+	return w, nil
+}
+
+func (c *testClient) Workflow(_ context.Context, _ string) (*circleci.Workflow, error) {
+	if c.workflowErr != nil {
+		return nil, c.workflowErr
+	}
+	w := c.workflows[0]
+	c.workflows = c.workflows[1:]
+	if w == nil {
+		return nil, &circleci.APIError{StatusCode: http.StatusNotFound}
+	}
+	return w, nil
+}
+
+func (c *testClient) Build(_ context.Context, _ uint64) (*circleci.BuildResponse, error) {
+	b := c.builds[0]
+	c.builds = c.builds[1:]
+	return b, nil
+}
+
+func TestWorkflow(t *testing.T) {
+	w := &circleci.Workflow{
+		ID:   "w1",
+		Name: "tests",
+		Jobs: []*circleci.Job{
+			{ID: "j1", Name: "unit"},
+			{ID: "j2", Name: "e2e"},
+			{ID: "j3", Name: "lint"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     *retry.Options
+		expected *circleci.RetryOptions
+		err      error
+	}{
+		{
+			"Full",
+			nil,
+			&circleci.RetryOptions{},
+			nil,
+		},
+		{
+			"FromFailed",
+			&retry.Options{FromFailed: true},
+			&circleci.RetryOptions{FromFailed: true},
+			nil,
+		},
+		{
+			"Jobs",
+			&retry.Options{Jobs: []string{"unit", "lint"}},
+			&circleci.RetryOptions{Jobs: []string{"j1", "j3"}},
+			nil,
+		},
+		{
+			"JobNotFound",
+			&retry.Options{Jobs: []string{"unit", "build"}},
+			nil,
+			retry.ErrJobNotFound,
+		},
+		{
+			"FromFailedWithJobs",
+			&retry.Options{FromFailed: true, Jobs: []string{"unit"}},
+			nil,
+			retry.ErrFromFailedWithJobs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{}
+
+			_, err := retry.Workflow(context.Background(), client, w, tt.opts)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if client.workflowID != w.ID {
+				t.Errorf("workflowID: got %q, wanted %q", client.workflowID, w.ID)
+			}
+
+			if !reflect.DeepEqual(client.opts, tt.expected) {
+				t.Errorf("opts: got %+v, wanted %+v", client.opts, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSH(t *testing.T) {
+	w := &circleci.Workflow{
+		ID:   "w1",
+		Name: "tests",
+		Jobs: []*circleci.Job{
+			{ID: "j1", Name: "unit"},
+			{ID: "j2", Name: "e2e"},
+		},
+	}
+	p := &circleci.Pipeline{ID: "p1", Workflows: []*circleci.Workflow{w}}
+	ssh := &circleci.BuildResponse{
+		Lifecycle: "running",
+		Nodes:     []*circleci.BuildNode{{PublicIPAddr: "1.2.3.4", Port: 64535, SSHEnabled: true}},
+	}
+
+	tests := []struct {
+		name              string
+		job               string
+		rr                *circleci.RetryResponse
+		pipelineWorkflows [][]*circleci.Workflow
+		workflows         []*circleci.Workflow
+		builds            []*circleci.BuildResponse
+		expected          string
+		err               error
+	}{
+		{
+			"Available",
+			"e2e",
+			nil,
+			nil,
+			[]*circleci.Workflow{
+				{ID: "w2", Jobs: []*circleci.Job{{ID: "j3", Name: "e2e"}}},
+				{ID: "w2", Jobs: []*circleci.Job{{ID: "j3", Name: "e2e", Number: 12}}},
+			},
+			[]*circleci.BuildResponse{
+				{Lifecycle: "queued"},
+				{Lifecycle: "running", Nodes: []*circleci.BuildNode{{}}},
+				{Lifecycle: "running", Nodes: []*circleci.BuildNode{{PublicIPAddr: "1.2.3.4", Port: 64535}}},
+				ssh,
+			},
+			"ssh -p 64535 1.2.3.4",
+			nil,
+		},
+		{
+			"WorkflowNotCreated",
+			"e2e",
+			nil,
+			nil,
+			[]*circleci.Workflow{
+				nil,
+				nil,
+				{ID: "w2", Jobs: []*circleci.Job{{ID: "j3", Name: "e2e", Number: 12}}},
+			},
+			[]*circleci.BuildResponse{ssh},
+			"ssh -p 64535 1.2.3.4",
+			nil,
+		},
+		{
+			"NoWorkflowID",
+			"e2e",
+			&circleci.RetryResponse{Message: "Accepted."},
+			[][]*circleci.Workflow{
+				{w},
+				{w, {ID: "w2", Name: "tests"}},
+			},
+			[]*circleci.Workflow{
+				{ID: "w2", Jobs: []*circleci.Job{{ID: "j3", Name: "e2e", Number: 12}}},
+			},
+			[]*circleci.BuildResponse{ssh},
+			"ssh -p 64535 1.2.3.4",
+			nil,
+		},
+		{
+			"NeverCreated",
+			"e2e",
+			&circleci.RetryResponse{Message: "Accepted."},
+			[][]*circleci.Workflow{{w}},
+			nil,
+			nil,
+			"",
+			retry.ErrNoNewWorkflow,
+		},
+		{
+			"Finished",
+			"e2e",
+			nil,
+			nil,
+			[]*circleci.Workflow{
+				{ID: "w2", Jobs: []*circleci.Job{{ID: "j3", Name: "e2e", Number: 12}}},
+			},
+			[]*circleci.BuildResponse{
+				{Lifecycle: "finished"},
+			},
+			"",
+			retry.ErrJobFinished,
+		},
+		{
+			"JobNotFound",
+			"lint",
+			nil,
+			nil,
+			nil,
+			nil,
+			"",
+			retry.ErrJobNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{
+				rr:                tt.rr,
+				pipelineWorkflows: tt.pipelineWorkflows,
+				workflows:         tt.workflows,
+				builds:            tt.builds,
+			}
+
+			got, err := retry.SSH(context.Background(), client, p, w, tt.job, time.Millisecond)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if got != tt.expected {
+				t.Errorf("got %q, wanted %q", got, tt.expected)
+			}
+
+			want := &circleci.RetryOptions{Jobs: []string{"j2"}, EnableSSH: true}
+			if !reflect.DeepEqual(client.opts, want) {
+				t.Errorf("opts: got %+v, wanted %+v", client.opts, want)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	updated := time.Now()
+	w := &circleci.Workflow{ID: "w1", Name: "tests", Status: "failed"}
+	p := &circleci.Pipeline{
+		ID:      "p1",
+		Number:  3,
+		Updated: &updated,
+		Workflows: []*circleci.Workflow{
+			w,
+			{ID: "w0", Name: "lint", Status: "success"},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		rr                *circleci.RetryResponse
+		pipelineWorkflows [][]*circleci.Workflow
+		workflows         []*circleci.Workflow
+		outcome           status.Outcome
+	}{
+		{
+			"WorkflowID",
+			&circleci.RetryResponse{WorkflowID: "w2"},
+			nil,
+			[]*circleci.Workflow{
+				{ID: "w2", Name: "tests", Status: "running"},
+				{ID: "w2", Name: "tests", Status: "success"},
+			},
+			status.OutcomeSuccess,
+		},
+		{
+			"FindInPipeline",
+			&circleci.RetryResponse{Message: "Accepted."},
+			[][]*circleci.Workflow{
+				{w, p.Workflows[1]},
+				{w, p.Workflows[1], {ID: "w2", Name: "tests", Status: "running"}},
+			},
+			[]*circleci.Workflow{
+				{ID: "w2", Name: "tests", Status: "failed"},
+			},
+			status.OutcomeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{
+				rr:                tt.rr,
+				pipelineWorkflows: tt.pipelineWorkflows,
+				workflows:         tt.workflows,
+			}
+
+			s, err := retry.Wait(context.Background(), client, p, w, tt.rr, time.Millisecond)
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if got := s.Outcome(); got != tt.outcome {
+				t.Errorf("Outcome: got %s, wanted %s", got, tt.outcome)
+			}
+
+			if got := s.Pipelines[0].Workflows; len(got) != 1 || got[0].ID != "w2" {
+				t.Errorf("Workflows: got %v, wanted only w2", got)
+			}
+		})
+	}
+}
+
+func TestWaitNoNewWorkflow(t *testing.T) {
+	w := &circleci.Workflow{ID: "w1", Name: "tests", Status: "failed"}
+	p := &circleci.Pipeline{ID: "p1", Workflows: []*circleci.Workflow{w}}
+
+	tests := []struct {
+		name   string
+		rr     *circleci.RetryResponse
+		client *testClient
+	}{
+		{
+			"NeverInPipeline",
+			&circleci.RetryResponse{Message: "Accepted."},
+			&testClient{pipelineWorkflows: [][]*circleci.Workflow{{w}}},
+		},
+		{
+			"WrongWorkflowID",
+			&circleci.RetryResponse{WorkflowID: "w-missing"},
+			&testClient{workflowErr: &circleci.APIError{StatusCode: http.StatusNotFound}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err := retry.Wait(ctx, tt.client, p, w, tt.rr, time.Millisecond)
+			if !errors.Is(err, retry.ErrNoNewWorkflow) {
+				t.Errorf("got %v, wanted %v", err, retry.ErrNoNewWorkflow)
+			}
+		})
+	}
+}
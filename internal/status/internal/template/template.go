@@ -4,50 +4,22 @@ package template
 
 import (
 	"bytes"
-	"fmt"
-	"math"
 	"text/template"
-	"time"
-)
-
-func since(t *time.Time) time.Duration {
-	return time.Since(*t)
-}
-
-func duration(duration time.Duration) string {
-	puralize := func(a int64, singular string) string {
-		if a > 1 {
-			return fmt.Sprintf("%d %ss", a, singular)
-		}
-		return fmt.Sprintf("%d %s", a, singular)
-	}
-
-	if days := int64(duration.Hours() / 24); days > 0 {
-		return puralize(days, "day")
-	}
-
-	if hours := int64(math.Mod(duration.Hours(), 24)); hours > 0 {
-		return puralize(hours, "hour")
-	}
-
-	if minutes := int64(math.Mod(duration.Minutes(), 60)); minutes > 0 {
-		return puralize(minutes, "minute")
-	}
-
-	if seconds := int64(math.Mod(duration.Seconds(), 60)); seconds > 0 {
-		return puralize(seconds, "second")
-	}
 
-	return ""
-}
+	"github.com/tmessi/cci/internal/render"
+)
 
 const status = `
 {{- range .Pipelines }}
 {{ .Number }} pipeline: {{ .Updated | since | duration }} ago
+  {{- with .Trigger }}{{ with .Actor }}, triggered by {{ .Login }}{{ end }}{{ end }}
+  {{- with .VCS }}
+  {{ .Revision | short }}{{ with .Commit }} {{ .Subject }}{{ end }}
+  {{- end }}
   {{- range .Workflows }}
-  {{ .Name }}:
+  {{ .Name }}:{{ if .Created }} {{ between .Created .Stopped | elapsed }}{{ end }}
     {{- range .Jobs }}
-    {{ .Number | printf "%-2d" }} {{ .Name | printf "%-30s" }} {{ .Status }}
+    {{ .Number | printf "%-2d" }} {{ .Name | printf "%-30s" }} {{ if .Started }}{{ .Status | printf "%-12s" }} {{ between .Started .Stopped | elapsed }}{{ else }}{{ .Status }}{{ end }}
     {{- end }}
   {{- end -}}
 {{- end -}}`
@@ -55,11 +27,7 @@ const status = `
 var tmpl *template.Template
 
 func init() {
-	funcMap := template.FuncMap{
-		"since":    since,
-		"duration": duration,
-	}
-	tmpl, _ = template.New("status").Funcs(funcMap).Parse(status)
+	tmpl, _ = template.New("status").Funcs(render.Funcs()).Parse(status)
 }
 
 // Render will render the given data using the template.
@@ -3,26 +3,206 @@ package status
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/render"
 	"github.com/tmessi/cci/internal/status/internal/template"
+	"gopkg.in/yaml.v3"
 )
 
 // Known errors.
 var (
-	ErrNoBranch = errors.New("no branch provided")
+	ErrNoBranch      = errors.New("no branch, commit or tag provided")
+	ErrUnknownFormat = errors.New("unknown format")
+)
+
+// Formats that a Status can be rendered in.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
 )
 
 // Status reports the status of a set of CI workflows for a branch.
 type Status struct {
-	Pipelines []*circleci.Pipeline
+	Pipelines []*circleci.Pipeline `json:"pipelines" yaml:"pipelines"`
 }
 
 func (s *Status) String() string {
 	return template.Render(s)
 }
 
+// Template renders the Status with a user supplied text/template.
+// The template has the functions provided by the render package.
+func (s *Status) Template(text string) (string, error) {
+	return render.Template(text, s)
+}
+
+// Format renders the Status in the given format. An empty format
+// is the same as FormatText.
+func (s *Status) Format(format string) (string, error) {
+	switch format {
+	case "", FormatText:
+		return s.String(), nil
+	case FormatJSON:
+		b, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case FormatYAML:
+		b, err := yaml.Marshal(s)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(b), "\n"), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// Workflow statuses reported by CircleCI.
+//
+// https://circleci.com/docs/api/v2/#operation/getWorkflowById
+const (
+	WorkflowSuccess      = "success"
+	WorkflowRunning      = "running"
+	WorkflowNotRun       = "not_run"
+	WorkflowFailed       = "failed"
+	WorkflowError        = "error"
+	WorkflowFailing      = "failing"
+	WorkflowOnHold       = "on_hold"
+	WorkflowCanceled     = "canceled"
+	WorkflowUnauthorized = "unauthorized"
+)
+
+// Done reports whether every workflow of the newest pipeline has stopped
+// running. A workflow that is on hold is waiting on someone to approve it,
+// so it is considered done. A workflow or job with an unknown status is not
+// done, matching its outcome.
+func (s *Status) Done() bool {
+	if len(s.Pipelines) <= 0 || len(s.Pipelines[0].Workflows) <= 0 {
+		return false
+	}
+
+	for _, w := range s.Pipelines[0].Workflows {
+		if w.Status == WorkflowFailing || outcome(w.Status) == OutcomeRunning {
+			return false
+		}
+		for _, j := range w.Jobs {
+			if _, ok := outcomes[j.Status]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Outcome is the overall result of the newest pipeline.
+type Outcome int
+
+// Possible outcomes, see Status.Outcome.
+const (
+	OutcomeSuccess Outcome = iota
+	OutcomeFailed
+	OutcomeRunning
+	OutcomeOnHold
+	OutcomeCanceled
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailed:
+		return "failed"
+	case OutcomeRunning:
+		return "running"
+	case OutcomeOnHold:
+		return "on hold"
+	case OutcomeCanceled:
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// severity orders the outcomes so that the most significant is reported.
+var severity = map[Outcome]int{
+	OutcomeSuccess:  0,
+	OutcomeCanceled: 1,
+	OutcomeOnHold:   2,
+	OutcomeRunning:  3,
+	OutcomeFailed:   4,
+}
+
+// outcomes maps workflow and job statuses to an Outcome.
+//
+// https://circleci.com/docs/api/v2/#operation/listWorkflowJobs
+var outcomes = map[string]Outcome{
+	WorkflowSuccess:       OutcomeSuccess,
+	WorkflowNotRun:        OutcomeSuccess,
+	"retried":             OutcomeSuccess,
+	WorkflowFailed:        OutcomeFailed,
+	WorkflowError:         OutcomeFailed,
+	WorkflowFailing:       OutcomeFailed,
+	WorkflowUnauthorized:  OutcomeFailed,
+	"infrastructure_fail": OutcomeFailed,
+	"timedout":            OutcomeFailed,
+	"terminated-unknown":  OutcomeFailed,
+	WorkflowRunning:       OutcomeRunning,
+	"queued":              OutcomeRunning,
+	"not_running":         OutcomeRunning,
+	"blocked":             OutcomeRunning,
+	WorkflowOnHold:        OutcomeOnHold,
+	WorkflowCanceled:      OutcomeCanceled,
+}
+
+// outcome maps a workflow or job status to an Outcome. A status that is not
+// known, e.g. one added by CircleCI, is reported as OutcomeRunning so that
+// it is never mistaken for a success.
+func outcome(status string) Outcome {
+	if o, ok := outcomes[status]; ok {
+		return o
+	}
+	return OutcomeRunning
+}
+
+// Outcome determines the overall result of the newest pipeline from the
+// status of its workflows and jobs. A failure anywhere is reported
+// as OutcomeFailed, even if other workflows are still running. Otherwise
+// running takes precedence over on hold, which takes precedence over canceled.
+// Whether a workflow is running, on hold or canceled is taken from the
+// workflow, since the jobs after an approval job are blocked while the
+// workflow is on hold. Jobs are only used to find failures, and statuses
+// that are not known.
+// A Status without any workflows is considered running.
+func (s *Status) Outcome() Outcome {
+	if len(s.Pipelines) <= 0 || len(s.Pipelines[0].Workflows) <= 0 {
+		return OutcomeRunning
+	}
+
+	o := OutcomeSuccess
+	worst := func(n Outcome) {
+		if severity[n] > severity[o] {
+			o = n
+		}
+	}
+
+	for _, w := range s.Pipelines[0].Workflows {
+		worst(outcome(w.Status))
+		for _, j := range w.Jobs {
+			if n, ok := outcomes[j.Status]; !ok || n == OutcomeFailed {
+				worst(outcome(j.Status))
+			}
+		}
+	}
+	return o
+}
+
 // Workflow returns the Workflow with the given name.
 // If no Workflow is found, it will return nil.
 func (s *Status) Workflow(name string) *circleci.Workflow {
@@ -53,12 +233,12 @@ func (s *Status) Job(workflowName, name string) *circleci.Job {
 }
 
 type client interface {
-	Pipelines(context.Context, string, uint64) ([]*circleci.Pipeline, error)
+	Pipelines(context.Context, *circleci.PipelineFilter, uint64) ([]*circleci.Pipeline, error)
 }
 
-// Check queries CircleCI for the status of a set of Jobs for the given Project and branch.
-func Check(ctx context.Context, c client, branch string, limit uint64) (*Status, error) {
-	if branch == "" {
+// Check queries CircleCI for the status of a set of Jobs for the pipelines matching the filter.
+func Check(ctx context.Context, c client, f *circleci.PipelineFilter, limit uint64) (*Status, error) {
+	if f.Branch == "" && f.Revision == "" && f.Tag == "" {
 		return nil, ErrNoBranch
 	}
 
@@ -66,7 +246,7 @@ func Check(ctx context.Context, c client, branch string, limit uint64) (*Status,
 		limit = 1
 	}
 
-	p, err := c.Pipelines(ctx, branch, limit)
+	p, err := c.Pipelines(ctx, f, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/tmessi/cci/internal/circleci"
 	"github.com/tmessi/cci/internal/status"
@@ -14,7 +15,7 @@ type testClient struct {
 	err       error
 }
 
-func (c *testClient) Pipelines(_ context.Context, _ string, _ uint64) ([]*circleci.Pipeline, error) {
+func (c *testClient) Pipelines(_ context.Context, _ *circleci.PipelineFilter, _ uint64) ([]*circleci.Pipeline, error) {
 	return c.pipelines, c.err
 }
 
@@ -134,7 +135,7 @@ func TestCheck(t *testing.T) {
 			client := &testClient{tt.pipelines, tt.err}
 
 			ctx := context.Background()
-			s, err := status.Check(ctx, client, tt.branch, uint64(len(tt.pipelines)))
+			s, err := status.Check(ctx, client, &circleci.PipelineFilter{Branch: tt.branch}, uint64(len(tt.pipelines)))
 
 			if tt.expectedError != nil {
 				if err == nil {
@@ -192,3 +193,291 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat(t *testing.T) {
+	created := time.Date(2021, 10, 24, 20, 2, 11, 0, time.UTC)
+	updated := time.Date(2021, 10, 24, 20, 8, 57, 0, time.UTC)
+	s := &status.Status{
+		Pipelines: []*circleci.Pipeline{
+			{
+				ID:      "11111111-1111-1111-0000-111111111111",
+				Number:  7,
+				State:   "created",
+				Created: &created,
+				Updated: &updated,
+				VCS: &circleci.VCS{
+					Revision: "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39",
+					Branch:   "main",
+					Commit: &circleci.Commit{
+						Subject: "Add status metadata",
+					},
+				},
+				Trigger: &circleci.Trigger{
+					Type: "webhook",
+					Actor: &circleci.Actor{
+						Login: "tmessi",
+					},
+				},
+				Workflows: []*circleci.Workflow{
+					{
+						ID:      "11111111-1111-1111-1111-111111111111",
+						Name:    "tests",
+						Status:  "success",
+						Created: &created,
+						Stopped: &updated,
+						Jobs: []*circleci.Job{
+							{
+								ID:      "11111111-1111-1111-1111-111111111112",
+								Name:    "unit",
+								Number:  1,
+								Status:  "success",
+								Type:    "build",
+								Started: &created,
+								Stopped: &updated,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+		err      error
+	}{
+		{
+			"JSON",
+			status.FormatJSON,
+			`{
+  "pipelines": [
+    {
+      "id": "11111111-1111-1111-0000-111111111111",
+      "number": 7,
+      "state": "created",
+      "created_at": "2021-10-24T20:02:11Z",
+      "updated_at": "2021-10-24T20:08:57Z",
+      "vcs": {
+        "revision": "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39",
+        "branch": "main",
+        "commit": {
+          "subject": "Add status metadata"
+        }
+      },
+      "trigger": {
+        "type": "webhook",
+        "actor": {
+          "login": "tmessi"
+        }
+      },
+      "workflows": [
+        {
+          "id": "11111111-1111-1111-1111-111111111111",
+          "name": "tests",
+          "status": "success",
+          "created_at": "2021-10-24T20:02:11Z",
+          "stopped_at": "2021-10-24T20:08:57Z",
+          "jobs": [
+            {
+              "id": "11111111-1111-1111-1111-111111111112",
+              "job_number": 1,
+              "name": "unit",
+              "status": "success",
+              "type": "build",
+              "started_at": "2021-10-24T20:02:11Z",
+              "stopped_at": "2021-10-24T20:08:57Z"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`,
+			nil,
+		},
+		{
+			"YAML",
+			status.FormatYAML,
+			`pipelines:
+    - id: 11111111-1111-1111-0000-111111111111
+      number: 7
+      state: created
+      created_at: 2021-10-24T20:02:11Z
+      updated_at: 2021-10-24T20:08:57Z
+      vcs:
+        revision: 4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39
+        branch: main
+        commit:
+            subject: Add status metadata
+      trigger:
+        type: webhook
+        actor:
+            login: tmessi
+      workflows:
+        - id: 11111111-1111-1111-1111-111111111111
+          name: tests
+          status: success
+          created_at: 2021-10-24T20:02:11Z
+          stopped_at: 2021-10-24T20:08:57Z
+          jobs:
+            - id: 11111111-1111-1111-1111-111111111112
+              job_number: 1
+              name: unit
+              status: success
+              type: build
+              started_at: 2021-10-24T20:02:11Z
+              stopped_at: 2021-10-24T20:08:57Z`,
+			nil,
+		},
+		{
+			"Unknown",
+			"xml",
+			"",
+			status.ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := s.Format(tt.format)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if out != tt.expected {
+				t.Errorf("got:\n%s\nwanted:\n%s", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	s := &status.Status{
+		Pipelines: []*circleci.Pipeline{
+			{
+				Number: 7,
+				Workflows: []*circleci.Workflow{
+					{Name: "tests", Status: "success"},
+					{Name: "deploy", Status: "on_hold"},
+				},
+			},
+		},
+	}
+
+	text := `{{ .Outcome }}{{ range .Pipelines }} #{{ .Number }}{{ range .Workflows }} {{ .Name }}={{ .Status }}{{ end }}{{ end }}`
+	out, err := s.Template(text)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if expected := "on hold #7 tests=success deploy=on_hold"; out != expected {
+		t.Errorf("got %q, wanted %q", out, expected)
+	}
+}
+
+func TestString(t *testing.T) {
+	created := time.Date(2021, 10, 24, 20, 2, 11, 0, time.UTC)
+	started := time.Date(2021, 10, 24, 20, 2, 15, 0, time.UTC)
+	stopped := time.Date(2021, 10, 24, 20, 8, 57, 0, time.UTC)
+	// The age of the pipeline is relative to now, so only its
+	// largest unit is shown.
+	updated := time.Now().Add(-3*time.Hour - 10*time.Minute)
+
+	s := &status.Status{
+		Pipelines: []*circleci.Pipeline{
+			{
+				Number:  7,
+				Created: &created,
+				Updated: &updated,
+				VCS: &circleci.VCS{
+					Revision: "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39",
+					Commit:   &circleci.Commit{Subject: "Add status metadata"},
+				},
+				Trigger: &circleci.Trigger{Actor: &circleci.Actor{Login: "tmessi"}},
+				Workflows: []*circleci.Workflow{
+					{
+						Name:    "tests",
+						Status:  "success",
+						Created: &created,
+						Stopped: &stopped,
+						Jobs: []*circleci.Job{
+							{Name: "unit", Number: 1, Status: "success", Started: &started, Stopped: &stopped},
+							{Name: "lint", Number: 2, Status: "blocked"},
+						},
+					},
+					{
+						Name:   "deploy",
+						Status: "on_hold",
+					},
+				},
+			},
+		},
+	}
+
+	expected := `
+7 pipeline: 3 hours ago, triggered by tmessi
+  4f2a9c1 Add status metadata
+  tests: 6m 46s
+    1  unit                           success      6m 42s
+    2  lint                           blocked
+  deploy:`
+	if out := s.String(); out != expected {
+		t.Errorf("got:\n%s\nwanted:\n%s", out, expected)
+	}
+}
+
+func TestDone(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		jobs     []string
+		done     bool
+		outcome  status.Outcome
+	}{
+		{"NoWorkflows", nil, nil, false, status.OutcomeRunning},
+		{"AllSuccess", []string{"success", "success"}, []string{"success"}, true, status.OutcomeSuccess},
+		{"NotRun", []string{"success", "not_run"}, nil, true, status.OutcomeSuccess},
+		{"Running", []string{"success", "running"}, []string{"queued"}, false, status.OutcomeRunning},
+		{"Failing", []string{"failing", "running"}, []string{"failed"}, false, status.OutcomeFailed},
+		{"Failed", []string{"success", "failed"}, []string{"timedout"}, true, status.OutcomeFailed},
+		{"OnHold", []string{"canceled", "on_hold"}, []string{"on_hold"}, true, status.OutcomeOnHold},
+		{"Canceled", []string{"canceled"}, []string{"canceled"}, true, status.OutcomeCanceled},
+		{"OnHoldBlocked", []string{"on_hold"}, []string{"success", "on_hold", "blocked"}, true, status.OutcomeOnHold},
+		{"RunningBlocked", []string{"running"}, []string{"success", "running", "blocked"}, false, status.OutcomeRunning},
+		{"FailedBlocked", []string{"failing"}, []string{"failed", "blocked"}, false, status.OutcomeFailed},
+		{"UnknownWorkflow", []string{"success", "brand_new"}, nil, false, status.OutcomeRunning},
+		{"UnknownJob", []string{"success"}, []string{"success", ""}, false, status.OutcomeRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &circleci.Pipeline{}
+			for _, st := range tt.statuses {
+				w := &circleci.Workflow{Status: st}
+				for _, js := range tt.jobs {
+					w.Jobs = append(w.Jobs, &circleci.Job{Status: js})
+				}
+				p.Workflows = append(p.Workflows, w)
+			}
+			s := &status.Status{Pipelines: []*circleci.Pipeline{p}}
+
+			if got := s.Done(); got != tt.done {
+				t.Errorf("Done: got %t, wanted %t", got, tt.done)
+			}
+
+			if got := s.Outcome(); got != tt.outcome {
+				t.Errorf("Outcome: got %s, wanted %s", got, tt.outcome)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package template provides template formating for the output
+// of the tests command.
+package template
+
+import (
+	"bytes"
+	"text/template"
+)
+
+const tests = `
+{{- .Passed }} passed, {{ .Failed }} failed, {{ .Skipped }} skipped
+{{- range .Failures }}
+
+FAIL: {{ .Name }}
+  classname: {{ .Classname }}
+  {{- with .File }}
+  file:      {{ . }}
+  {{- end }}
+  {{- with .Message }}
+
+{{ . }}
+  {{- end }}
+{{- end -}}`
+
+var tmpl *template.Template
+
+func init() {
+	tmpl, _ = template.New("tests").Parse(tests)
+}
+
+// Render will render the given data using the template.
+func Render(data interface{}) string {
+	var b bytes.Buffer
+	err := tmpl.Execute(&b, data)
+	if err != nil {
+		panic(err)
+	}
+	return b.String()
+}
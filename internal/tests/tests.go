@@ -0,0 +1,162 @@
+// Package tests is used to summarize the test results of a job.
+package tests
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/tests/internal/template"
+)
+
+// Known errors.
+var (
+	ErrNoTests       = errors.New("no test results found")
+	ErrUnknownFormat = errors.New("unknown format")
+)
+
+// Formats that a Summary can be rendered in.
+const (
+	FormatText  = "text"
+	FormatJUnit = "junit"
+)
+
+// Summary reports the test results of a job.
+type Summary struct {
+	Passed   int
+	Failed   int
+	Skipped  int
+	Failures []*circleci.TestMetadata
+
+	tests []*circleci.TestMetadata
+}
+
+func (s *Summary) String() string {
+	return template.Render(s)
+}
+
+// Format renders the Summary in the given format. An empty format
+// is the same as FormatText.
+func (s *Summary) Format(format string) (string, error) {
+	switch format {
+	case "", FormatText:
+		return s.String(), nil
+	case FormatJUnit:
+		return s.junit()
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+type client interface {
+	TestMetadata(context.Context, uint64) ([]*circleci.TestMetadata, error)
+}
+
+// Summarize retrieves the test results for the given job number
+// and summarizes them.
+func Summarize(ctx context.Context, c client, jobNum uint64) (*Summary, error) {
+	results, err := c.TestMetadata(ctx, jobNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) <= 0 {
+		return nil, ErrNoTests
+	}
+
+	s := &Summary{tests: results}
+	for _, t := range results {
+		switch t.Result {
+		case circleci.TestSuccess:
+			s.Passed++
+		case circleci.TestSkipped:
+			s.Skipped++
+		default:
+			s.Failed++
+			s.Failures = append(s.Failures, t)
+		}
+	}
+	return s, nil
+}
+
+// junit types are used to marshal a Summary into a JUnit XML report.
+//
+// https://github.com/testmoapp/junitxml
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      float64          `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// junit renders the test results as a JUnit XML report, with a test suite
+// for each classname.
+func (s *Summary) junit() (string, error) {
+	report := &junitTestSuites{
+		Tests:    len(s.tests),
+		Failures: s.Failed,
+		Skipped:  s.Skipped,
+	}
+
+	suites := make(map[string]*junitTestSuite)
+	for _, t := range s.tests {
+		suite, ok := suites[t.Classname]
+		if !ok {
+			suite = &junitTestSuite{Name: t.Classname}
+			suites[t.Classname] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+
+		tc := &junitTestCase{
+			Name:      t.Name,
+			Classname: t.Classname,
+			File:      t.File,
+			Time:      t.RunTime,
+		}
+		switch t.Result {
+		case circleci.TestSuccess:
+		case circleci.TestSkipped:
+			tc.Skipped = &junitMessage{Message: t.Message}
+			suite.Skipped++
+		default:
+			tc.Failure = &junitMessage{Body: t.Message}
+			suite.Failures++
+		}
+
+		suite.Tests++
+		suite.Time += t.RunTime
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(b), nil
+}
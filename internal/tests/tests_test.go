@@ -0,0 +1,126 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/tests"
+)
+
+type testClient struct {
+	results []*circleci.TestMetadata
+	err     error
+}
+
+func (c *testClient) TestMetadata(_ context.Context, _ uint64) ([]*circleci.TestMetadata, error) {
+	return c.results, c.err
+}
+
+var results = []*circleci.TestMetadata{
+	{Name: "TestBuild", Classname: "github.com/tmessi/cci/internal/circleci", Result: "success", RunTime: 0.5},
+	{Name: "TestRetry", Classname: "github.com/tmessi/cci/internal/circleci", Result: "failure", File: "circleci_test.go", Message: "got 2, wanted 3", RunTime: 0.25},
+	{Name: "TestCheck", Classname: "github.com/tmessi/cci/internal/status", Result: "skipped"},
+}
+
+func TestSummarize(t *testing.T) {
+	cases := []struct {
+		name     string
+		results  []*circleci.TestMetadata
+		err      error
+		format   string
+		expected string
+		expErr   error
+	}{
+		{
+			"Error",
+			nil,
+			errors.New("error"),
+			"",
+			"",
+			errors.New("error"),
+		},
+		{
+			"NoTests",
+			nil,
+			nil,
+			"",
+			"",
+			tests.ErrNoTests,
+		},
+		{
+			"Text",
+			results,
+			nil,
+			tests.FormatText,
+			`1 passed, 1 failed, 1 skipped
+
+FAIL: TestRetry
+  classname: github.com/tmessi/cci/internal/circleci
+  file:      circleci_test.go
+
+got 2, wanted 3`,
+			nil,
+		},
+		{
+			"JUnit",
+			results,
+			nil,
+			tests.FormatJUnit,
+			`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" skipped="1">
+  <testsuite name="github.com/tmessi/cci/internal/circleci" tests="2" failures="1" skipped="0" time="0.75">
+    <testcase name="TestBuild" classname="github.com/tmessi/cci/internal/circleci" time="0.5"></testcase>
+    <testcase name="TestRetry" classname="github.com/tmessi/cci/internal/circleci" file="circleci_test.go" time="0.25">
+      <failure>got 2, wanted 3</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="github.com/tmessi/cci/internal/status" tests="1" failures="0" skipped="1" time="0">
+    <testcase name="TestCheck" classname="github.com/tmessi/cci/internal/status" time="0">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`,
+			nil,
+		},
+		{
+			"UnknownFormat",
+			results,
+			nil,
+			"tap",
+			"",
+			tests.ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{tt.results, tt.err}
+
+			s, err := tests.Summarize(context.Background(), client, 1)
+			var out string
+			if err == nil {
+				out, err = s.Format(tt.format)
+			}
+
+			if tt.expErr != nil {
+				if err == nil {
+					t.Fatalf("expected error, but did not get one")
+				}
+				if !errors.Is(err, tt.expErr) && err.Error() != tt.expErr.Error() {
+					t.Errorf("got %q, wanted %q", err.Error(), tt.expErr.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if out != tt.expected {
+				t.Errorf("got:\n%s\nwanted:\n%s", out, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Package trigger is used to trigger new pipelines.
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/status"
+	"golang.org/x/sync/errgroup"
+)
+
+// Known errors.
+var (
+	ErrBranchAndTag     = errors.New("cannot trigger a pipeline for both a branch and a tag")
+	ErrInvalidParameter = errors.New("parameter must be of the form key=value")
+	ErrPipelineErrored  = errors.New("pipeline errored")
+	ErrNoWorkflows      = errors.New("pipeline has no workflows")
+)
+
+// States of a pipeline.
+const (
+	// pipelineCreated is the state of a pipeline once its workflows
+	// have been created.
+	pipelineCreated = "created"
+	// pipelineErrored is the state of a pipeline that could not be
+	// created, for example because of an invalid configuration.
+	pipelineErrored = "errored"
+)
+
+type client interface {
+	TriggerPipeline(context.Context, *circleci.TriggerOptions) (*circleci.Pipeline, error)
+	Pipeline(context.Context, string) (*circleci.Pipeline, error)
+	Workflows(context.Context, *circleci.Pipeline) ([]*circleci.Workflow, error)
+	Workflow(context.Context, string) (*circleci.Workflow, error)
+	Concurrency() int
+}
+
+// ParseParameters parses parameters of the form key=value. Only the values
+// true and false are parsed as a bool, other values are parsed as an int
+// if possible, otherwise they are a string.
+func ParseParameters(params []string) (map[string]interface{}, error) {
+	if len(params) <= 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]interface{}, len(params))
+	for _, p := range params {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidParameter, p)
+		}
+
+		if value == "true" || value == "false" {
+			parsed[key] = value == "true"
+		} else if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			parsed[key] = i
+		} else {
+			parsed[key] = value
+		}
+	}
+	return parsed, nil
+}
+
+// Pipeline triggers a new pipeline for the branch or tag with the given parameters.
+func Pipeline(ctx context.Context, c client, branch, tag string, params map[string]interface{}) (*circleci.Pipeline, error) {
+	if branch != "" && tag != "" {
+		return nil, ErrBranchAndTag
+	}
+
+	return c.TriggerPipeline(ctx, &circleci.TriggerOptions{
+		Branch:     branch,
+		Tag:        tag,
+		Parameters: params,
+	})
+}
+
+// Wait polls the pipeline every interval until all of its workflows are done.
+// It returns the status of the pipeline, including the jobs of each workflow.
+// If the pipeline was created without any workflows, ErrNoWorkflows is
+// returned.
+func Wait(ctx context.Context, c client, p *circleci.Pipeline, interval time.Duration) (*status.Status, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pipeline, err := c.Pipeline(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if pipeline.State == pipelineErrored {
+			return nil, fmt.Errorf("%w: %d", ErrPipelineErrored, pipeline.Number)
+		}
+
+		pipeline.Workflows, err = c.Workflows(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		// Every workflow may be skipped by a when condition,
+		// in which case there is nothing to wait for.
+		if pipeline.State == pipelineCreated && len(pipeline.Workflows) <= 0 {
+			return nil, fmt.Errorf("%w: %d", ErrNoWorkflows, pipeline.Number)
+		}
+
+		s := &status.Status{Pipelines: []*circleci.Pipeline{pipeline}}
+		if s.Done() {
+			if err := jobs(ctx, c, pipeline); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobs retrieves the jobs of each workflow in the pipeline, bounded by
+// the client's concurrency.
+func jobs(ctx context.Context, c client, p *circleci.Pipeline) error {
+	g, gctx := errgroup.WithContext(ctx)
+	if n := c.Concurrency(); n > 0 {
+		g.SetLimit(n)
+	}
+
+	for i := range p.Workflows {
+		i := i // https://golang.org/doc/faq#closures_and_goroutines
+
+		g.Go(func() error {
+			w, err := c.Workflow(gctx, p.Workflows[i].ID)
+			if err != nil {
+				return err
+			}
+			p.Workflows[i] = w
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
@@ -0,0 +1,209 @@
+package trigger_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tmessi/cci/internal/circleci"
+	"github.com/tmessi/cci/internal/status"
+	"github.com/tmessi/cci/internal/trigger"
+)
+
+func TestParseParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   []string
+		expected map[string]interface{}
+		err      error
+	}{
+		{"None", nil, nil, nil},
+		{
+			"Types",
+			[]string{"run_nightly=true", "count=3", "image=cimg/go:1.19", "empty="},
+			map[string]interface{}{
+				"run_nightly": true,
+				"count":       int64(3),
+				"image":       "cimg/go:1.19",
+				"empty":       "",
+			},
+			nil,
+		},
+		{
+			"NotBools",
+			[]string{"one=1", "zero=0", "t=t", "f=F", "upper=TRUE"},
+			map[string]interface{}{
+				"one":   int64(1),
+				"zero":  int64(0),
+				"t":     "t",
+				"f":     "F",
+				"upper": "TRUE",
+			},
+			nil,
+		},
+		{"NoValue", []string{"run_nightly"}, nil, trigger.ErrInvalidParameter},
+		{"NoKey", []string{"=true"}, nil, trigger.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := trigger.ParseParameters(tt.params)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, wanted %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+type testClient struct {
+	opts *circleci.TriggerOptions
+
+	// pipelines and workflows are returned in order on each call.
+	pipelines []*circleci.Pipeline
+	workflows [][]*circleci.Workflow
+
+	concurrency int
+}
+
+func (c *testClient) Concurrency() int {
+	return c.concurrency
+}
+
+func (c *testClient) TriggerPipeline(_ context.Context, opts *circleci.TriggerOptions) (*circleci.Pipeline, error) {
+	c.opts = opts
+	return &circleci.Pipeline{ID: "p1", Number: 4, State: "created"}, nil
+}
+
+func (c *testClient) Pipeline(_ context.Context, _ string) (*circleci.Pipeline, error) {
+	p := c.pipelines[0]
+	c.pipelines = c.pipelines[1:]
+	return p, nil
+}
+
+func (c *testClient) Workflows(_ context.Context, _ *circleci.Pipeline) ([]*circleci.Workflow, error) {
+	w := c.workflows[0]
+	c.workflows = c.workflows[1:]
+	return w, nil
+}
+
+func (c *testClient) Workflow(_ context.Context, id string) (*circleci.Workflow, error) {
+	return &circleci.Workflow{
+		ID:     id,
+		Status: "success",
+		Jobs:   []*circleci.Job{{Name: "build", Status: "success"}},
+	}, nil
+}
+
+func TestPipeline(t *testing.T) {
+	client := &testClient{}
+
+	if _, err := trigger.Pipeline(context.Background(), client, "main", "v1.0.0", nil); !errors.Is(err, trigger.ErrBranchAndTag) {
+		t.Errorf("got %v, wanted %v", err, trigger.ErrBranchAndTag)
+	}
+
+	params := map[string]interface{}{"nightly": true}
+	p, err := trigger.Pipeline(context.Background(), client, "", "v1.0.0", params)
+	if err != nil {
+		t.Fatalf("err: %s", err.Error())
+	}
+
+	if p.Number != 4 {
+		t.Errorf("Number: got %d, wanted %d", p.Number, 4)
+	}
+
+	want := &circleci.TriggerOptions{Tag: "v1.0.0", Parameters: params}
+	if !reflect.DeepEqual(client.opts, want) {
+		t.Errorf("opts: got %+v, wanted %+v", client.opts, want)
+	}
+}
+
+func TestWait(t *testing.T) {
+	updated := time.Now()
+
+	tests := []struct {
+		name      string
+		pipelines []*circleci.Pipeline
+		workflows [][]*circleci.Workflow
+		outcome   status.Outcome
+		err       error
+	}{
+		{
+			"Success",
+			[]*circleci.Pipeline{
+				{ID: "p1", State: "setup", Updated: &updated},
+				{ID: "p1", State: "created", Updated: &updated},
+				{ID: "p1", State: "created", Updated: &updated},
+			},
+			[][]*circleci.Workflow{
+				nil,
+				{{ID: "w1", Status: "running"}},
+				{{ID: "w1", Status: "success"}},
+			},
+			status.OutcomeSuccess,
+			nil,
+		},
+		{
+			"NoWorkflows",
+			[]*circleci.Pipeline{
+				{ID: "p1", State: "setup", Updated: &updated},
+				{ID: "p1", State: "created", Updated: &updated},
+			},
+			[][]*circleci.Workflow{
+				nil,
+				nil,
+			},
+			status.OutcomeRunning,
+			trigger.ErrNoWorkflows,
+		},
+		{
+			"Errored",
+			[]*circleci.Pipeline{
+				{ID: "p1", State: "errored", Updated: &updated},
+			},
+			nil,
+			status.OutcomeFailed,
+			trigger.ErrPipelineErrored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{pipelines: tt.pipelines, workflows: tt.workflows, concurrency: 2}
+
+			s, err := trigger.Wait(context.Background(), client, &circleci.Pipeline{ID: "p1"}, time.Millisecond)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, wanted %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err: %s", err.Error())
+			}
+
+			if got := s.Outcome(); got != tt.outcome {
+				t.Errorf("Outcome: got %s, wanted %s", got, tt.outcome)
+			}
+
+			if jobs := s.Pipelines[0].Workflows[0].Jobs; len(jobs) != 1 {
+				t.Errorf("Jobs: got %d, wanted %d", len(jobs), 1)
+			}
+		})
+	}
+}